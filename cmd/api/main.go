@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 
+	"github.com/Gildaciolopes/fintrack-api/internal/alerts"
 	"github.com/Gildaciolopes/fintrack-api/internal/config"
 	"github.com/Gildaciolopes/fintrack-api/internal/handler"
 	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/recurring"
 	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/Gildaciolopes/fintrack-api/internal/storage"
+	"github.com/Gildaciolopes/fintrack-api/internal/trash"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -29,32 +34,94 @@ const VERSION = "1.0.0"
 	goalRepo := repository.NewGoalRepository(db)
 	budgetRepo := repository.NewBudgetRepository(db)
 	dashboardRepo := repository.NewDashboardRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+	recurringRuleRepo := repository.NewRecurringRuleRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	backupRepo := repository.NewBackupRepository(db)
+	categorizationRuleRepo := repository.NewCategorizationRuleRepository(db)
+	payeeRepo := repository.NewPayeeRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	budgetTemplateRepo := repository.NewBudgetTemplateRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	budgetAlertRepo := repository.NewBudgetAlertRepository(db)
+ 
+	attachmentStore, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment storage: %v", err)
+	}
+
+	alertEvaluator := alerts.NewEvaluator(budgetRepo, budgetAlertRepo, alerts.NewNotifiers(cfg.Alerts)...)
  
 	healthHandler := handler.NewHealthHandler(VERSION)
 	categoryHandler := handler.NewCategoryHandler(categoryRepo)
-	transactionHandler := handler.NewTransactionHandler(transactionRepo)
+	transactionHandler := handler.NewTransactionHandler(transactionRepo, categorizationRuleRepo, payeeRepo, accountRepo, alertEvaluator)
 	goalHandler := handler.NewGoalHandler(goalRepo)
-	budgetHandler := handler.NewBudgetHandler(budgetRepo)
+	budgetHandler := handler.NewBudgetHandler(budgetRepo, budgetTemplateRepo)
+	budgetTemplateHandler := handler.NewBudgetTemplateHandler(budgetTemplateRepo, budgetRepo)
 	dashboardHandler := handler.NewDashboardHandler(dashboardRepo, transactionRepo)
+	accountHandler := handler.NewAccountHandler(accountRepo)
+	transferHandler := handler.NewTransferHandler(transactionRepo, accountRepo)
+	recurringRuleHandler := handler.NewRecurringRuleHandler(recurringRuleRepo, accountRepo)
+	tagHandler := handler.NewTagHandler(tagRepo)
+	settingsHandler := handler.NewSettingsHandler(settingsRepo)
+	categorizationRuleHandler := handler.NewCategorizationRuleHandler(categorizationRuleRepo, transactionRepo)
+	payeeHandler := handler.NewPayeeHandler(payeeRepo, transactionRepo)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentRepo, transactionRepo, attachmentStore, cfg.Storage.MaxAttachmentSize)
+	suggestionHandler := handler.NewSuggestionHandler(transactionRepo, categoryRepo)
+	trashHandler := handler.NewTrashHandler(transactionRepo, categoryRepo, budgetRepo, goalRepo, cfg.Jobs.TrashRetention)
+	alertHandler := handler.NewAlertHandler(budgetAlertRepo)
+	auditHandler := handler.NewAuditHandler(auditRepo, transactionRepo)
+	importHandler := handler.NewImportHandler(transactionRepo, accountRepo, categorizationRuleRepo, payeeRepo, alertEvaluator)
+	exportHandler := handler.NewExportHandler(transactionRepo, accountRepo)
+	backupHandler := handler.NewBackupHandler(
+		backupRepo,
+		settingsRepo,
+		categoryRepo,
+		accountRepo,
+		tagRepo,
+		payeeRepo,
+		recurringRuleRepo,
+		categorizationRuleRepo,
+		transactionRepo,
+		budgetRepo,
+		budgetTemplateRepo,
+		goalRepo,
+		attachmentStore,
+	)
  
 	authMiddleware := middleware.NewAuthMiddleware(cfg.Supabase.JWTSecret)
+
+	materializer := recurring.NewMaterializer(recurringRuleRepo, alertEvaluator, cfg.Jobs.RecurringInterval)
+	go materializer.Start(context.Background())
+
+	purger := trash.NewPurger(trashRepo, attachmentStore, cfg.Jobs.TrashRetention, cfg.Jobs.TrashPurgeInterval)
+	go purger.Start(context.Background())
  
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	r := gin.New()
+	// The client IP is recorded in the audit log: only trust X-Forwarded-For
+	// from the configured proxies.
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
 	r.Use(gin.Recovery())
+	r.Use(middleware.RequestID())
 	r.Use(middleware.Logger())
 	r.Use(middleware.ErrorHandler())
 	r.Use(middleware.SecurityHeaders())
+	r.Use(middleware.MoneyFormat())
 
 	// CORS configuration
 	corsConfig := cors.Config{
 		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Money-Format"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID"},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600,
 	}
@@ -75,10 +142,24 @@ const VERSION = "1.0.0"
 		protected := api.Group("")
 		protected.Use(authMiddleware.Authenticate())
 		{ 
+			protected.GET("/settings", settingsHandler.Get)
+			protected.PUT("/settings", settingsHandler.Update)
+
+			protected.GET("/backup", backupHandler.Export)
+			protected.POST("/backup/restore", backupHandler.Restore)
+
+			protected.GET("/trash", trashHandler.List)
+			protected.GET("/activity", auditHandler.Activity)
+
+			protected.GET("/alerts", alertHandler.GetAll)
+			protected.POST("/alerts/:id/read", alertHandler.MarkRead)
+
 			dashboard := protected.Group("/dashboard")
 			{
 				dashboard.GET("/stats", dashboardHandler.GetStats)
 				dashboard.GET("/expenses-by-category", dashboardHandler.GetExpensesByCategory)
+				dashboard.GET("/by-tag", dashboardHandler.GetByTag)
+				dashboard.GET("/top-payees", dashboardHandler.GetTopPayees)
 				dashboard.GET("/monthly-data", dashboardHandler.GetMonthlyData)
 				dashboard.GET("/daily-data", dashboardHandler.GetDailyData)
 				dashboard.GET("/recent-transactions", dashboardHandler.GetRecentTransactions)
@@ -91,15 +172,91 @@ const VERSION = "1.0.0"
 				categories.GET("/:id", categoryHandler.GetByID)
 				categories.PUT("/:id", categoryHandler.Update)
 				categories.DELETE("/:id", categoryHandler.Delete)
+				categories.POST("/:id/restore", trashHandler.RestoreCategory)
+			}
+ 
+			tags := protected.Group("/tags")
+			{
+				tags.POST("", tagHandler.Create)
+				tags.GET("", tagHandler.GetAll)
+				tags.GET("/:id", tagHandler.GetByID)
+				tags.PUT("/:id", tagHandler.Update)
+				tags.DELETE("/:id", tagHandler.Delete)
+			}
+ 
+			accounts := protected.Group("/accounts")
+			{
+				accounts.POST("", accountHandler.Create)
+				accounts.GET("", accountHandler.GetAll)
+				accounts.GET("/:id", accountHandler.GetByID)
+				accounts.GET("/:id/balance", accountHandler.GetBalance)
+				accounts.PUT("/:id", accountHandler.Update)
+				accounts.DELETE("/:id", accountHandler.Delete)
 			}
  
 			transactions := protected.Group("/transactions")
 			{
 				transactions.POST("", transactionHandler.Create)
 				transactions.GET("", transactionHandler.GetAll)
+				transactions.GET("/export", exportHandler.ExportTransactions)
+				transactions.GET("/suggest-category", suggestionHandler.SuggestCategory)
+				transactions.GET("/duplicates", transactionHandler.GetDuplicates)
+				transactions.POST("/duplicates/merge", transactionHandler.MergeDuplicates)
+				transactions.POST("/bulk", transactionHandler.BulkCreate)
+				transactions.PUT("/bulk", transactionHandler.BulkUpdate)
+				transactions.DELETE("/bulk", transactionHandler.BulkDelete)
 				transactions.GET("/:id", transactionHandler.GetByID)
 				transactions.PUT("/:id", transactionHandler.Update)
 				transactions.DELETE("/:id", transactionHandler.Delete)
+				transactions.POST("/:id/restore", trashHandler.RestoreTransaction)
+				transactions.GET("/:id/history", auditHandler.TransactionHistory)
+				transactions.POST("/:id/attachments", attachmentHandler.Upload)
+				transactions.GET("/:id/attachments", attachmentHandler.GetAll)
+				transactions.GET("/:id/attachments/:attachmentId", attachmentHandler.Download)
+				transactions.DELETE("/:id/attachments/:attachmentId", attachmentHandler.Delete)
+			}
+ 
+			imports := protected.Group("/imports")
+			{
+				imports.POST("/csv", importHandler.ImportCSV)
+				imports.POST("/ofx", importHandler.ImportOFX)
+			}
+ 
+			transfers := protected.Group("/transfers")
+			{
+				transfers.POST("", transferHandler.Create)
+				transfers.GET("/:id", transferHandler.GetByID)
+				transfers.DELETE("/:id", transferHandler.Delete)
+			}
+ 
+			recurringRules := protected.Group("/recurring-rules")
+			{
+				recurringRules.POST("", recurringRuleHandler.Create)
+				recurringRules.GET("", recurringRuleHandler.GetAll)
+				recurringRules.GET("/:id", recurringRuleHandler.GetByID)
+				recurringRules.GET("/:id/preview", recurringRuleHandler.Preview)
+				recurringRules.PUT("/:id", recurringRuleHandler.Update)
+				recurringRules.DELETE("/:id", recurringRuleHandler.Delete)
+			}
+ 
+			categorizationRules := protected.Group("/categorization-rules")
+			{
+				categorizationRules.POST("", categorizationRuleHandler.Create)
+				categorizationRules.GET("", categorizationRuleHandler.GetAll)
+				categorizationRules.POST("/apply", categorizationRuleHandler.Apply)
+				categorizationRules.GET("/:id", categorizationRuleHandler.GetByID)
+				categorizationRules.PUT("/:id", categorizationRuleHandler.Update)
+				categorizationRules.DELETE("/:id", categorizationRuleHandler.Delete)
+			}
+ 
+			payees := protected.Group("/payees")
+			{
+				payees.POST("", payeeHandler.Create)
+				payees.GET("", payeeHandler.GetAll)
+				payees.POST("/apply", payeeHandler.Apply)
+				payees.GET("/:id", payeeHandler.GetByID)
+				payees.PUT("/:id", payeeHandler.Update)
+				payees.DELETE("/:id", payeeHandler.Delete)
 			}
  
 			goals := protected.Group("/goals")
@@ -109,6 +266,7 @@ const VERSION = "1.0.0"
 				goals.GET("/:id", goalHandler.GetByID)
 				goals.PUT("/:id", goalHandler.Update)
 				goals.DELETE("/:id", goalHandler.Delete)
+				goals.POST("/:id/restore", trashHandler.RestoreGoal)
 				goals.POST("/:id/contribute", goalHandler.Contribute)
 			}
  
@@ -117,9 +275,22 @@ const VERSION = "1.0.0"
 				budgets.POST("", budgetHandler.Create)
 				budgets.GET("", budgetHandler.GetAll)
 				budgets.GET("/with-spent", budgetHandler.GetBudgetsWithSpent)
+				budgets.POST("/copy", budgetHandler.Copy)
+				budgets.POST("/generate", budgetHandler.Generate)
 				budgets.GET("/:id", budgetHandler.GetByID)
 				budgets.PUT("/:id", budgetHandler.Update)
 				budgets.DELETE("/:id", budgetHandler.Delete)
+				budgets.POST("/:id/restore", trashHandler.RestoreBudget)
+			}
+ 
+			budgetTemplates := protected.Group("/budget-templates")
+			{
+				budgetTemplates.POST("", budgetTemplateHandler.Create)
+				budgetTemplates.GET("", budgetTemplateHandler.GetAll)
+				budgetTemplates.GET("/:id", budgetTemplateHandler.GetByID)
+				budgetTemplates.PUT("/:id", budgetTemplateHandler.Update)
+				budgetTemplates.DELETE("/:id", budgetTemplateHandler.Delete)
+				budgetTemplates.POST("/:id/apply", budgetTemplateHandler.Apply)
 			}
 		}
 	}
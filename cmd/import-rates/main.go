@@ -0,0 +1,75 @@
+// Command import-rates loads reference exchange rates from a local file into
+// the exchange_rates table.
+//
+//	go run ./cmd/import-rates -file eurofxref-hist.xml
+//	go run ./cmd/import-rates -file eurofxref-hist.csv -format csv
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/config"
+	"github.com/Gildaciolopes/fintrack-api/internal/fx"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+)
+
+func main() {
+	file := flag.String("file", "", "path to an ECB XML or CSV rates file")
+	format := flag.String("format", "", "xml or csv (defaults to the file extension)")
+	source := flag.String("source", "ecb", "source label stored with each rate")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open rates file: %v", err)
+	}
+	defer f.Close()
+
+	var rates []models.ExchangeRate
+	switch *format {
+	case "xml":
+		rates, err = fx.ParseECBXML(f)
+	case "csv":
+		rates, err = fx.ParseCSV(f)
+	default:
+		log.Fatalf("Unsupported format %q (use xml or csv)", *format)
+	}
+	if err != nil {
+		log.Fatalf("Failed to parse rates: %v", err)
+	}
+
+	for i := range rates {
+		rates[i].Source = *source
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := cfg.ConnectDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := repository.NewExchangeRateRepository(db).Upsert(rates); err != nil {
+		log.Fatalf("Failed to store rates: %v", err)
+	}
+
+	log.Printf("Imported %d exchange rates from %s", len(rates), *file)
+}
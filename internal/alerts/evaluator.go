@@ -0,0 +1,103 @@
+// Package alerts raises budget alerts when the spend of a budget crosses one
+// of its thresholds, and hands them to notifiers.
+package alerts
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Evaluator checks the budgets of a user against their alert thresholds.
+// Alerts are recorded once per budget, threshold and month, so checking the
+// same month again only raises the thresholds crossed since.
+type Evaluator struct {
+	budgetRepo *repository.BudgetRepository
+	repo       *repository.BudgetAlertRepository
+	notifiers  []Notifier
+}
+
+func NewEvaluator(budgetRepo *repository.BudgetRepository, repo *repository.BudgetAlertRepository, notifiers ...Notifier) *Evaluator {
+	return &Evaluator{
+		budgetRepo: budgetRepo,
+		repo:       repo,
+		notifiers:  notifiers,
+	}
+}
+
+// Check evaluates the user's budgets in the months of the given dates, the
+// dates of expenses that were created or changed. New alerts are returned
+// and sent to the notifiers in the background.
+func (e *Evaluator) Check(userID uuid.UUID, dates ...time.Time) ([]models.BudgetAlert, error) {
+	seen := make(map[time.Time]bool)
+
+	var raised []models.BudgetAlert
+	for _, date := range dates {
+		month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if seen[month] {
+			continue
+		}
+		seen[month] = true
+
+		budgets, err := e.budgetRepo.GetBudgetsWithSpent(userID, month)
+		if err != nil {
+			return nil, err
+		}
+
+		var crossed []models.BudgetAlert
+		for _, budget := range budgets {
+			for _, threshold := range budget.AlertThresholds {
+				if !reached(budget, threshold) {
+					continue
+				}
+				crossed = append(crossed, models.BudgetAlert{
+					UserID:     userID,
+					BudgetID:   budget.ID,
+					CategoryID: budget.CategoryID,
+					Month:      month,
+					Threshold:  threshold,
+					Available:  budget.Available,
+					Spent:      budget.Spent,
+					Percentage: budget.Percentage,
+					Category:   budget.Category,
+				})
+			}
+		}
+
+		recorded, err := e.repo.Record(crossed)
+		if err != nil {
+			return nil, err
+		}
+		raised = append(raised, recorded...)
+	}
+
+	if len(raised) > 0 && len(e.notifiers) > 0 {
+		go e.notify(raised)
+	}
+
+	return raised, nil
+}
+
+// reached tells whether the spend of the budget is at or over the threshold.
+// When rollover left nothing available, any spend is over every threshold.
+func reached(budget models.BudgetWithSpent, threshold int64) bool {
+	if budget.Available <= 0 {
+		return budget.Spent > 0
+	}
+	return budget.Percentage >= float64(threshold)
+}
+
+func (e *Evaluator) notify(raised []models.BudgetAlert) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, notifier := range e.notifiers {
+		if err := notifier.Notify(ctx, raised); err != nil {
+			log.Printf("alerts: notifier failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/config"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+// Notifier delivers new budget alerts, e.g. by e-mail or push notification.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []models.BudgetAlert) error
+}
+
+// NewNotifiers builds the notifiers enabled in the configuration. Alerts are
+// always logged.
+func NewNotifiers(cfg config.AlertsConfig) []Notifier {
+	notifiers := []Notifier{LogNotifier{}}
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.WebhookURL))
+	}
+	return notifiers
+}
+
+// LogNotifier writes the alerts to the application log.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, alerts []models.BudgetAlert) error {
+	for _, alert := range alerts {
+		log.Printf("Budget alert: user %s, budget %s reached %d%% (%s of %s)",
+			alert.UserID, alert.BudgetID, alert.Threshold, alert.Spent, alert.Available)
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the alerts as JSON, {"alerts": [...]}, to a URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alerts []models.BudgetAlert) error {
+	body, err := json.Marshal(map[string]interface{}{"alerts": alerts})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
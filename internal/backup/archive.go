@@ -0,0 +1,210 @@
+// Package backup reads and writes user backup archives: a ZIP holding a
+// manifest.json plus one JSON document per entity (categories.json,
+// transactions.json...).
+package backup
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+const (
+	// Format identifies FinTrack backup archives.
+	Format = "fintrack-backup"
+	// Version is the archive layout written by this build. Archives with a
+	// higher version are rejected; entity files missing from older versions
+	// are read as empty.
+	Version = 1
+
+	manifestFile = "manifest.json"
+
+	// maxFileSize caps the uncompressed size of each archive file.
+	maxFileSize = 256 << 20
+)
+
+// Entity names, which are also the archive file names without ".json".
+const (
+	EntitySettings            = "settings"
+	EntityCategories          = "categories"
+	EntityAccounts            = "accounts"
+	EntityTags                = "tags"
+	EntityPayees              = "payees"
+	EntityRecurringRules      = "recurring_rules"
+	EntityCategorizationRules = "categorization_rules"
+	EntityTransactions        = "transactions"
+	EntityBudgets             = "budgets"
+	EntityBudgetTemplates     = "budget_templates"
+	EntityGoals               = "goals"
+)
+
+// entityTargets maps every entity to its field in models.BackupData. New
+// entities only need an entry here to be read from archives.
+func entityTargets(data *models.BackupData) map[string]interface{} {
+	return map[string]interface{}{
+		EntitySettings:            &data.Settings,
+		EntityCategories:          &data.Categories,
+		EntityAccounts:            &data.Accounts,
+		EntityTags:                &data.Tags,
+		EntityPayees:              &data.Payees,
+		EntityRecurringRules:      &data.RecurringRules,
+		EntityCategorizationRules: &data.CategorizationRules,
+		EntityTransactions:        &data.Transactions,
+		EntityBudgets:             &data.Budgets,
+		EntityBudgetTemplates:     &data.BudgetTemplates,
+		EntityGoals:               &data.Goals,
+	}
+}
+
+// Writer writes a backup archive entity by entity.
+type Writer struct {
+	zip      *zip.Writer
+	manifest models.BackupManifest
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		zip: zip.NewWriter(w),
+		manifest: models.BackupManifest{
+			Format:    Format,
+			Version:   Version,
+			CreatedAt: time.Now().UTC(),
+			Entities:  make(map[string]int),
+		},
+	}
+}
+
+// Add writes an entity document. value is a slice of records or a single
+// record (counted as one).
+func (w *Writer) Add(entity string, value interface{}) error {
+	file, err := w.zip.Create(entity + ".json")
+	if err != nil {
+		return err
+	}
+
+	count := 1
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice {
+		count = v.Len()
+		if v.IsNil() {
+			value = []struct{}{}
+		}
+	}
+	w.manifest.Entities[entity] = count
+
+	return json.NewEncoder(file).Encode(value)
+}
+
+// Stream writes an entity document as a JSON array whose elements are
+// produced one at a time by fn, so large entities need not fit in memory.
+func (w *Writer) Stream(entity string, fn func(add func(record interface{}) error) error) error {
+	file, err := w.zip.Create(entity + ".json")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(file, "["); err != nil {
+		return err
+	}
+
+	count := 0
+	err = fn(func(record interface{}) error {
+		if count > 0 {
+			if _, err := io.WriteString(file, ","); err != nil {
+				return err
+			}
+		}
+		count++
+
+		encoded, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(encoded)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	w.manifest.Entities[entity] = count
+	_, err = io.WriteString(file, "]\n")
+	return err
+}
+
+// Close writes the manifest and finishes the archive.
+func (w *Writer) Close() error {
+	file, err := w.zip.Create(manifestFile)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(w.manifest); err != nil {
+		return err
+	}
+
+	return w.zip.Close()
+}
+
+// Read opens a backup archive and decodes its manifest and entities.
+func Read(r io.ReaderAt, size int64) (*models.BackupManifest, *models.BackupData, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ZIP archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	manifestZip, ok := files[manifestFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s not found", manifestFile)
+	}
+
+	var manifest models.BackupManifest
+	if err := decodeFile(manifestZip, &manifest); err != nil {
+		return nil, nil, err
+	}
+
+	if manifest.Format != Format {
+		return nil, nil, fmt.Errorf("not a FinTrack backup (format %q)", manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > Version {
+		return nil, nil, fmt.Errorf("unsupported backup version %d (supported up to %d)", manifest.Version, Version)
+	}
+
+	data := &models.BackupData{}
+	for entity, target := range entityTargets(data) {
+		file, ok := files[entity+".json"]
+		if !ok {
+			continue
+		}
+		if err := decodeFile(file, target); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &manifest, data, nil
+}
+
+func decodeFile(file *zip.File, target interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	limited := io.LimitReader(reader, maxFileSize)
+	if err := json.NewDecoder(limited).Decode(target); err != nil {
+		return fmt.Errorf("%s: %w", file.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// maxProblems limits how many problems a ValidationError lists.
+const maxProblems = 50
+
+// ValidationError lists the inconsistencies found in a backup.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid backup: " + strings.Join(e.Problems, "; ")
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	if len(v.problems) < maxProblems {
+		v.problems = append(v.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// ids collects the IDs of an entity, reporting duplicates.
+func (v *validator) ids(entity string, count int, id func(i int) uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, count)
+	for i := 0; i < count; i++ {
+		if set[id(i)] {
+			v.addf("%s: duplicate id %s", entity, id(i))
+		}
+		set[id(i)] = true
+	}
+	return set
+}
+
+func (v *validator) ref(set map[uuid.UUID]bool, id *uuid.UUID, format string, args ...interface{}) {
+	if id != nil && !set[*id] {
+		v.addf(format, append(args, *id)...)
+	}
+}
+
+// Validate checks that every reference in the backup (transactions, budgets
+// and budget templates to categories, transactions and rules to accounts, and
+// so on) points to a record in the same backup, and that transactions are
+// consistent (positive amounts, splits adding up, transfers with two legs).
+func Validate(data *models.BackupData) error {
+	v := &validator{}
+
+	categories := v.ids(EntityCategories, len(data.Categories), func(i int) uuid.UUID { return data.Categories[i].ID })
+	accounts := v.ids(EntityAccounts, len(data.Accounts), func(i int) uuid.UUID { return data.Accounts[i].ID })
+	tags := v.ids(EntityTags, len(data.Tags), func(i int) uuid.UUID { return data.Tags[i].ID })
+	payees := v.ids(EntityPayees, len(data.Payees), func(i int) uuid.UUID { return data.Payees[i].ID })
+	rules := v.ids(EntityRecurringRules, len(data.RecurringRules), func(i int) uuid.UUID { return data.RecurringRules[i].ID })
+	v.ids(EntityCategorizationRules, len(data.CategorizationRules), func(i int) uuid.UUID { return data.CategorizationRules[i].ID })
+	v.ids(EntityTransactions, len(data.Transactions), func(i int) uuid.UUID { return data.Transactions[i].ID })
+	v.ids(EntityBudgets, len(data.Budgets), func(i int) uuid.UUID { return data.Budgets[i].ID })
+	v.ids(EntityBudgetTemplates, len(data.BudgetTemplates), func(i int) uuid.UUID { return data.BudgetTemplates[i].ID })
+	v.ids(EntityGoals, len(data.Goals), func(i int) uuid.UUID { return data.Goals[i].ID })
+
+	for _, rule := range data.RecurringRules {
+		v.ref(categories, rule.CategoryID, "recurring rule %s: unknown category %s", rule.ID)
+		v.ref(accounts, rule.AccountID, "recurring rule %s: unknown account %s", rule.ID)
+	}
+
+	for _, rule := range data.CategorizationRules {
+		v.ref(categories, rule.SetCategoryID, "categorization rule %s: unknown category %s", rule.ID)
+		for _, tagID := range rule.SetTagIDs {
+			tagID := tagID
+			v.ref(tags, &tagID, "categorization rule %s: unknown tag %s", rule.ID)
+		}
+	}
+
+	transferLegs := make(map[uuid.UUID]int)
+	for _, transaction := range data.Transactions {
+		if transaction.Type != "income" && transaction.Type != "expense" {
+			v.addf("transaction %s: invalid type %q", transaction.ID, transaction.Type)
+		}
+		if transaction.Amount <= 0 {
+			v.addf("transaction %s: amount must be positive", transaction.ID)
+		}
+
+		v.ref(categories, transaction.CategoryID, "transaction %s: unknown category %s", transaction.ID)
+		v.ref(accounts, transaction.AccountID, "transaction %s: unknown account %s", transaction.ID)
+		v.ref(payees, transaction.PayeeID, "transaction %s: unknown payee %s", transaction.ID)
+		v.ref(rules, transaction.RecurringRuleID, "transaction %s: unknown recurring rule %s", transaction.ID)
+
+		if len(transaction.Splits) > 0 {
+			var total models.Money
+			for _, split := range transaction.Splits {
+				total += split.Amount
+				v.ref(categories, split.CategoryID, "transaction %s: split with unknown category %s", transaction.ID)
+			}
+			if total != transaction.Amount {
+				v.addf("transaction %s: splits add up to %s, not %s", transaction.ID, total, transaction.Amount)
+			}
+		}
+
+		for _, tag := range transaction.Tags {
+			tagID := tag.ID
+			v.ref(tags, &tagID, "transaction %s: unknown tag %s", transaction.ID)
+		}
+
+		if transaction.TransferID != nil {
+			transferLegs[*transaction.TransferID]++
+		}
+	}
+
+	for transferID, legs := range transferLegs {
+		if legs != 2 {
+			v.addf("transfer %s: expected 2 transactions, found %d", transferID, legs)
+		}
+	}
+
+	for _, budget := range data.Budgets {
+		categoryID := budget.CategoryID
+		v.ref(categories, &categoryID, "budget %s: unknown category %s", budget.ID)
+	}
+
+	for _, template := range data.BudgetTemplates {
+		for _, item := range template.Items {
+			categoryID := item.CategoryID
+			v.ref(categories, &categoryID, "budget template %s: unknown category %s", template.ID)
+		}
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Package classifier suggests a category for a transaction from the ones the
+// user chose before, with a multinomial naive Bayes model over the words of
+// the description, the transaction type and the order of magnitude of the
+// amount.
+package classifier
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// accents folds the accented letters found in Portuguese descriptions, so
+// "AÇOUGUE" and "acougue" are the same word.
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Words splits a description into lower-case, accent-free words. Numbers
+// (dates, card and document numbers) and single letters are dropped, as they
+// rarely say anything about the category.
+func Words(description string) []string {
+	fields := strings.FieldsFunc(accents.Replace(strings.ToLower(description)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := fields[:0]
+	for _, field := range fields {
+		if len(field) < 2 || strings.IndexFunc(field, unicode.IsLetter) < 0 {
+			continue
+		}
+		words = append(words, field)
+	}
+	return words
+}
+
+// features returns the words of the description plus one token for the type
+// and one for the amount, bucketed by powers of two of the whole amount.
+func features(description, transactionType string, amount models.Money) []string {
+	tokens := Words(description)
+	if transactionType != "" {
+		tokens = append(tokens, "type:"+transactionType)
+	}
+	if amount > 0 {
+		tokens = append(tokens, fmt.Sprintf("amount:%d", bits.Len64(uint64(amount/100))))
+	}
+	return tokens
+}
+
+type class struct {
+	documents int
+	tokens    int
+	counts    map[string]int
+}
+
+// Model is a trained classifier. It is read-only once built, so it can be
+// shared between goroutines.
+type Model struct {
+	classes    map[uuid.UUID]*class
+	vocabulary map[string]bool
+	documents  int
+}
+
+// Suggestion is a candidate category with the model's confidence in it,
+// between 0 and 1, rounded to four decimal places.
+type Suggestion struct {
+	CategoryID uuid.UUID
+	Confidence float64
+}
+
+// Train builds a model from categorized transactions. Transactions without a
+// category or description, transfers and split transactions are ignored.
+func Train(transactions []models.Transaction) *Model {
+	model := &Model{
+		classes:    make(map[uuid.UUID]*class),
+		vocabulary: make(map[string]bool),
+	}
+
+	for _, transaction := range transactions {
+		if transaction.CategoryID == nil || transaction.Description == nil ||
+			transaction.TransferID != nil || len(transaction.Splits) > 0 {
+			continue
+		}
+
+		tokens := features(*transaction.Description, transaction.Type, transaction.Amount)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		c, ok := model.classes[*transaction.CategoryID]
+		if !ok {
+			c = &class{counts: make(map[string]int)}
+			model.classes[*transaction.CategoryID] = c
+		}
+
+		c.documents++
+		model.documents++
+		for _, token := range tokens {
+			c.counts[token]++
+			c.tokens++
+			model.vocabulary[token] = true
+		}
+	}
+
+	return model
+}
+
+// Documents returns how many transactions the model was trained on.
+func (m *Model) Documents() int {
+	return m.documents
+}
+
+// Suggest ranks the categories for a new transaction, most likely first, and
+// returns at most limit of them. It returns nothing when none of the words of
+// the description was seen in training, since the type and amount alone are
+// not enough to tell categories apart.
+func (m *Model) Suggest(description, transactionType string, amount models.Money, limit int) []Suggestion {
+	known := false
+	for _, word := range Words(description) {
+		if m.vocabulary[word] {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil
+	}
+
+	tokens := features(description, transactionType, amount)
+	vocabulary := float64(len(m.vocabulary))
+
+	// Log-probabilities with Laplace smoothing; tokens never seen in
+	// training are ignored.
+	suggestions := make([]Suggestion, 0, len(m.classes))
+	scores := make([]float64, 0, len(m.classes))
+	best := math.Inf(-1)
+	for categoryID, c := range m.classes {
+		score := math.Log(float64(c.documents) / float64(m.documents))
+		for _, token := range tokens {
+			if !m.vocabulary[token] {
+				continue
+			}
+			score += math.Log((float64(c.counts[token]) + 1) / (float64(c.tokens) + vocabulary))
+		}
+
+		suggestions = append(suggestions, Suggestion{CategoryID: categoryID})
+		scores = append(scores, score)
+		best = math.Max(best, score)
+	}
+
+	// Normalize into probabilities (softmax), shifting by the best score to
+	// avoid underflow.
+	var total float64
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - best)
+		total += scores[i]
+	}
+	for i := range suggestions {
+		suggestions[i].Confidence = math.Round(scores[i]/total*10000) / 10000
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].CategoryID.String() < suggestions[j].CategoryID.String()
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -18,12 +19,18 @@ type Config struct {
 	Supabase SupabaseConfig
 	JWT      JWTConfig
 	CORS     CORSConfig
+	Jobs     JobsConfig
+	Storage  StorageConfig
+	Alerts   AlertsConfig
 }
  
 type ServerConfig struct {
 	Port       string
 	Env        string
 	APIVersion string
+	// TrustedProxies are the addresses or CIDRs of the proxies whose
+	// X-Forwarded-For header is trusted for the client IP; none by default.
+	TrustedProxies []string
 }
  
 type DatabaseConfig struct {
@@ -50,6 +57,40 @@ type JWTConfig struct {
 type CORSConfig struct {
 	AllowedOrigins []string
 }
+
+type JobsConfig struct {
+	RecurringInterval time.Duration
+	// TrashRetention is how long deleted records stay in the trash before
+	// the purge job, run every TrashPurgeInterval, removes them for good.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+}
+
+// AlertsConfig enables the notifiers of budget alerts besides the log:
+// WebhookURL, when set, receives every new alert as a JSON POST.
+type AlertsConfig struct {
+	WebhookURL string
+}
+
+// StorageConfig selects where attachment files are kept: "local" (a
+// directory) or "s3" (any S3-compatible service, such as MinIO).
+type StorageConfig struct {
+	Driver            string
+	LocalPath         string
+	S3                S3Config
+	MaxAttachmentSize int64
+}
+
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses objects as endpoint/bucket/key, as MinIO expects,
+	// instead of bucket.endpoint/key.
+	PathStyle bool
+}
  
 func Load() (*Config, error) { 
 	if err := godotenv.Load(); err != nil {
@@ -57,12 +98,36 @@ func Load() (*Config, error) {
 	}
 
 	expirationHours, _ := strconv.Atoi(getEnv("JWT_EXPIRATION_HOURS", "24"))
+	recurringMinutes, _ := strconv.Atoi(getEnv("RECURRING_INTERVAL_MINUTES", "60"))
+	if recurringMinutes <= 0 {
+		recurringMinutes = 60
+	}
+	trashRetentionDays, _ := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	if trashRetentionDays <= 0 {
+		trashRetentionDays = 30
+	}
+	trashPurgeMinutes, _ := strconv.Atoi(getEnv("TRASH_PURGE_INTERVAL_MINUTES", "60"))
+	if trashPurgeMinutes <= 0 {
+		trashPurgeMinutes = 60
+	}
+	var trustedProxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	maxAttachmentMB, _ := strconv.Atoi(getEnv("ATTACHMENT_MAX_SIZE_MB", "10"))
+	if maxAttachmentMB <= 0 {
+		maxAttachmentMB = 10
+	}
 
 	config := &Config{
 		Server: ServerConfig{
-			Port:       getEnv("PORT", "8080"),
-			Env:        getEnv("ENV", "development"),
-			APIVersion: getEnv("API_VERSION", "v1"),
+			Port:           getEnv("PORT", "8080"),
+			Env:            getEnv("ENV", "development"),
+			APIVersion:     getEnv("API_VERSION", "v1"),
+			TrustedProxies: trustedProxies,
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -85,6 +150,27 @@ func Load() (*Config, error) {
 		CORS: CORSConfig{
 			AllowedOrigins: strings.Split(getEnv("ALLOWED_ORIGINS", "http://localhost:3000"), ","),
 		},
+		Jobs: JobsConfig{
+			RecurringInterval:  time.Duration(recurringMinutes) * time.Minute,
+			TrashRetention:     time.Duration(trashRetentionDays) * 24 * time.Hour,
+			TrashPurgeInterval: time.Duration(trashPurgeMinutes) * time.Minute,
+		},
+		Storage: StorageConfig{
+			Driver:    getEnv("STORAGE_DRIVER", "local"),
+			LocalPath: getEnv("STORAGE_LOCAL_PATH", "./data/attachments"),
+			S3: S3Config{
+				Endpoint:        getEnv("S3_ENDPOINT", ""),
+				Region:          getEnv("S3_REGION", "us-east-1"),
+				Bucket:          getEnv("S3_BUCKET", ""),
+				AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+				PathStyle:       getEnv("S3_PATH_STYLE", "true") == "true",
+			},
+			MaxAttachmentSize: int64(maxAttachmentMB) << 20,
+		},
+		Alerts: AlertsConfig{
+			WebhookURL: getEnv("ALERT_WEBHOOK_URL", ""),
+		},
 	}
 
 	return config, nil
@@ -0,0 +1,151 @@
+// Package duplicates finds transactions that were probably entered twice,
+// for example typed in by hand and later imported from a bank statement.
+package duplicates
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/classifier"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Window is how far apart two duplicates can be dated: banks often post a
+// purchase a few days after it was made.
+const Window = 3 * 24 * time.Hour
+
+// minOverlap is the share of the words of the shorter description that must
+// also appear in the other one.
+const minOverlap = 0.5
+
+// Similar reports whether two transactions look like the same one: same type,
+// amount, currency (when known) and account, dated within Window of each
+// other and with similar descriptions. A missing description matches any
+// other, since manual entries often have none. Transfers are never
+// duplicates.
+func Similar(a, b *models.Transaction) bool {
+	if a.TransferID != nil || b.TransferID != nil {
+		return false
+	}
+	if a.Type != b.Type || a.Amount != b.Amount {
+		return false
+	}
+	if a.Currency != "" && b.Currency != "" && a.Currency != b.Currency {
+		return false
+	}
+	if (a.AccountID == nil) != (b.AccountID == nil) || (a.AccountID != nil && *a.AccountID != *b.AccountID) {
+		return false
+	}
+
+	gap := a.Date.Sub(b.Date)
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap > Window {
+		return false
+	}
+
+	return similarDescriptions(a.Description, b.Description)
+}
+
+func similarDescriptions(a, b *string) bool {
+	if a == nil || b == nil {
+		return true
+	}
+
+	wordsA, wordsB := set(classifier.Words(*a)), set(classifier.Words(*b))
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return true
+	}
+
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+
+	shorter := len(wordsA)
+	if len(wordsB) < shorter {
+		shorter = len(wordsB)
+	}
+	return float64(shared)/float64(shorter) >= minOverlap
+}
+
+func set(words []string) map[string]bool {
+	result := make(map[string]bool, len(words))
+	for _, word := range words {
+		result[word] = true
+	}
+	return result
+}
+
+// Find returns the IDs of the candidates that look like duplicates of the
+// transaction.
+func Find(transaction *models.Transaction, candidates []models.Transaction) []uuid.UUID {
+	var ids []uuid.UUID
+	for i := range candidates {
+		if candidates[i].ID != transaction.ID && Similar(transaction, &candidates[i]) {
+			ids = append(ids, candidates[i].ID)
+		}
+	}
+	return ids
+}
+
+// Groups clusters the transactions into groups of suspected duplicates,
+// linking every pair of similar transactions. Transactions without a
+// duplicate are left out. Groups are ordered by date, and so are the
+// transactions in each group.
+func Groups(transactions []models.Transaction) [][]models.Transaction {
+	sorted := make([]int, len(transactions))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return transactions[sorted[i]].Date.Before(transactions[sorted[j]].Date)
+	})
+
+	// Union-find over the indexes; only transactions within Window of each
+	// other need to be compared.
+	parent := make([]int, len(transactions))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if transactions[b].Date.Sub(transactions[a].Date) > Window {
+				break
+			}
+			if Similar(&transactions[a], &transactions[b]) {
+				parent[root(b)] = root(a)
+			}
+		}
+	}
+
+	members := make(map[int][]models.Transaction)
+	var roots []int
+	for _, i := range sorted {
+		r := root(i)
+		if _, ok := members[r]; !ok {
+			roots = append(roots, r)
+		}
+		members[r] = append(members[r], transactions[i])
+	}
+
+	var groups [][]models.Transaction
+	for _, r := range roots {
+		if len(members[r]) > 1 {
+			groups = append(groups, members[r])
+		}
+	}
+	return groups
+}
@@ -0,0 +1,145 @@
+// Package exporter writes transactions to files in formats other tools
+// understand: CSV for spreadsheets, NDJSON for scripts and OFX for
+// accounting software.
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Supported export formats.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatOFX    = "ofx"
+)
+
+// Writer writes transactions one at a time. Close writes any trailer and
+// flushes buffered output; it does not close the underlying io.Writer.
+type Writer interface {
+	Write(transaction *models.Transaction) error
+	Close() error
+}
+
+// Options holds the context a format needs besides the transactions.
+type Options struct {
+	// AccountNames resolves account IDs to names (CSV).
+	AccountNames map[uuid.UUID]string
+	// Statement describes the OFX statement header.
+	Statement Statement
+}
+
+// NewWriter returns a Writer for the given format.
+func NewWriter(format string, w io.Writer, options Options) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, options)
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatOFX:
+		return newOFXWriter(w, options.Statement)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+var csvHeader = []string{"id", "date", "type", "amount", "currency", "category", "account", "description", "tags"}
+
+type csvWriter struct {
+	writer       *csv.Writer
+	accountNames map[uuid.UUID]string
+}
+
+func newCSVWriter(w io.Writer, options Options) (*csvWriter, error) {
+	writer := &csvWriter{writer: csv.NewWriter(w), accountNames: options.AccountNames}
+	if err := writer.writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *csvWriter) Write(transaction *models.Transaction) error {
+	account := ""
+	if transaction.AccountID != nil {
+		account = w.accountNames[*transaction.AccountID]
+	}
+
+	description := ""
+	if transaction.Description != nil {
+		description = *transaction.Description
+	}
+
+	tags := make([]string, len(transaction.Tags))
+	for i, tag := range transaction.Tags {
+		tags[i] = tag.Name
+	}
+
+	return w.writer.Write([]string{
+		transaction.ID.String(),
+		transaction.Date.Format("2006-01-02"),
+		transaction.Type,
+		transaction.Amount.String(),
+		transaction.Currency,
+		CategoryName(transaction),
+		account,
+		description,
+		strings.Join(tags, "; "),
+	})
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(transaction *models.Transaction) error {
+	return w.encoder.Encode(transaction)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// CategoryName returns the category of a transaction; for split transactions
+// the categories of the lines, separated by "; ".
+func CategoryName(transaction *models.Transaction) string {
+	if len(transaction.Splits) == 0 {
+		if transaction.Category == nil {
+			return ""
+		}
+		return transaction.Category.Name
+	}
+
+	names := make([]string, 0, len(transaction.Splits))
+	for _, split := range transaction.Splits {
+		if split.Category != nil {
+			names = append(names, split.Category.Name)
+		}
+	}
+	return strings.Join(names, "; ")
+}
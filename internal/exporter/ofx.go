@@ -0,0 +1,121 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+// Statement is the header of an exported OFX statement: a single account,
+// whose transactions are all in its currency, and its balance at End.
+type Statement struct {
+	AccountID string
+	Currency  string
+	Start     time.Time
+	End       time.Time
+	Balance   models.Money
+}
+
+// ofxNameLength is the maximum length of the OFX NAME element.
+const ofxNameLength = 32
+
+// ofxWriter writes an OFX 2.2 (XML) bank statement. The FITID is the
+// transaction ID, so a re-import of the file is recognised as duplicate.
+type ofxWriter struct {
+	writer    *bufio.Writer
+	statement Statement
+}
+
+func newOFXWriter(w io.Writer, statement Statement) (*ofxWriter, error) {
+	writer := &ofxWriter{writer: bufio.NewWriter(w), statement: statement}
+
+	now := ofxDateTime(time.Now())
+	fmt.Fprintf(writer.writer, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>POR</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>0</TRNUID>
+<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS>
+<CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>FINTRACK</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>%s</DTSTART>
+<DTEND>%s</DTEND>
+`,
+		now,
+		escapeOFX(statement.Currency),
+		escapeOFX(statement.AccountID),
+		ofxDate(statement.Start),
+		ofxDate(statement.End),
+	)
+
+	return writer, writer.writer.Flush()
+}
+
+func (w *ofxWriter) Write(transaction *models.Transaction) error {
+	if transaction.Currency != w.statement.Currency {
+		return fmt.Errorf("transaction %s is in %s, not in the statement currency %s", transaction.ID, transaction.Currency, w.statement.Currency)
+	}
+
+	trnType, amount := "CREDIT", transaction.Amount
+	if transaction.Type == "expense" {
+		trnType, amount = "DEBIT", -transaction.Amount
+	}
+
+	name := CategoryName(transaction)
+	if transaction.Description != nil && *transaction.Description != "" {
+		name = *transaction.Description
+	}
+	if runes := []rune(name); len(runes) > ofxNameLength {
+		name = string(runes[:ofxNameLength])
+	}
+
+	fmt.Fprintf(w.writer, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID>",
+		trnType,
+		ofxDate(transaction.Date),
+		amount.String(),
+		transaction.ID.String(),
+	)
+	if name != "" {
+		fmt.Fprintf(w.writer, "<NAME>%s</NAME>", escapeOFX(name))
+	}
+	if category := CategoryName(transaction); category != "" {
+		fmt.Fprintf(w.writer, "<MEMO>%s</MEMO>", escapeOFX(category))
+	}
+	_, err := w.writer.WriteString("</STMTTRN>\n")
+	return err
+}
+
+func (w *ofxWriter) Close() error {
+	fmt.Fprintf(w.writer, `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`, w.statement.Balance.String(), ofxDate(w.statement.End))
+
+	return w.writer.Flush()
+}
+
+func ofxDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+func ofxDateTime(t time.Time) string {
+	return t.UTC().Format("20060102150405")
+}
+
+func escapeOFX(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
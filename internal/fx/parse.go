@@ -0,0 +1,155 @@
+// Package fx reads reference exchange rates from files published by the
+// European Central Bank, or from CSV exports in the same convention (units of
+// currency per 1 EUR).
+package fx
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+const dateLayout = "2006-01-02"
+
+// ecbEnvelope matches the ECB eurofxref XML files (daily, 90 days and full
+// history), where rates are nested as Cube[time] > Cube[currency, rate].
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECBXML parses an ECB eurofxref XML document.
+func ParseECBXML(r io.Reader) ([]models.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid ECB XML: %w", err)
+	}
+
+	var rates []models.ExchangeRate
+	for _, day := range envelope.Days {
+		date, err := time.Parse(dateLayout, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", day.Time, err)
+		}
+
+		for _, entry := range day.Rates {
+			rate, err := newRate(entry.Currency, date, entry.Rate)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no exchange rates found")
+	}
+
+	return rates, nil
+}
+
+// ParseCSV parses either the ECB wide layout, with a Date column followed by
+// one column per currency ("Date,USD,JPY,...", missing values as N/A), or a
+// long layout with "date,currency,rate" columns.
+func ParseCSV(r io.Reader) ([]models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	long := len(header) >= 3 &&
+		strings.EqualFold(header[0], "date") &&
+		strings.EqualFold(header[1], "currency") &&
+		strings.EqualFold(header[2], "rate")
+
+	if !strings.EqualFold(header[0], "date") {
+		return nil, fmt.Errorf("first CSV column must be the date")
+	}
+
+	var rates []models.ExchangeRate
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		date, err := time.Parse(dateLayout, strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, record[0])
+		}
+
+		if long {
+			if len(record) < 3 {
+				return nil, fmt.Errorf("line %d: expected date,currency,rate", line)
+			}
+			rate, err := newRate(record[1], date, record[2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rates = append(rates, rate)
+			continue
+		}
+
+		for i := 1; i < len(record) && i < len(header); i++ {
+			value := strings.TrimSpace(record[i])
+			if header[i] == "" || value == "" || strings.EqualFold(value, "N/A") {
+				continue
+			}
+			rate, err := newRate(header[i], date, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no exchange rates found")
+	}
+
+	return rates, nil
+}
+
+func newRate(currency string, date time.Time, value string) (models.ExchangeRate, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 {
+		return models.ExchangeRate{}, fmt.Errorf("invalid currency code %q", currency)
+	}
+
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate <= 0 {
+		return models.ExchangeRate{}, fmt.Errorf("invalid rate %q for %s", value, currency)
+	}
+
+	return models.ExchangeRate{
+		Currency: currency,
+		Date:     date,
+		Rate:     rate,
+	}, nil
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AccountHandler struct {
+	repo *repository.AccountRepository
+}
+
+func NewAccountHandler(repo *repository.AccountRepository) *AccountHandler {
+	return &AccountHandler{repo: repo}
+}
+
+func (h *AccountHandler) Create(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	account := &models.Account{
+		UserID:         userID,
+		Name:           req.Name,
+		Type:           req.Type,
+		OpeningBalance: req.OpeningBalance,
+		Currency:       currency,
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(account); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to create account",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Account created successfully",
+		Data:    account,
+	})
+}
+
+func (h *AccountHandler) GetAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	includeArchived := c.Query("include_archived") == "true"
+
+	accounts, err := h.repo.GetAll(userID, includeArchived)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve accounts",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if accounts == nil {
+		accounts = []models.Account{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    accounts,
+	})
+}
+
+func (h *AccountHandler) GetByID(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid account ID",
+		})
+		return
+	}
+
+	account, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Account not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    account,
+	})
+}
+
+func (h *AccountHandler) Update(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid account ID",
+		})
+		return
+	}
+
+	var req models.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Type != "" {
+		updates["type"] = req.Type
+	}
+	if req.OpeningBalance != nil {
+		updates["opening_balance"] = *req.OpeningBalance
+	}
+	if req.Currency != "" {
+		updates["currency"] = req.Currency
+	}
+	if req.Archived != nil {
+		updates["archived"] = *req.Archived
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to update account",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Account updated successfully",
+	})
+}
+
+func (h *AccountHandler) Delete(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid account ID",
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete account",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Account deleted successfully",
+	})
+}
+
+func (h *AccountHandler) GetBalance(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid account ID",
+		})
+		return
+	}
+
+	asOf := time.Now()
+	if asOfStr := c.Query("as_of"); asOfStr != "" {
+		parsed, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid as_of format (use YYYY-MM-DD)",
+			})
+			return
+		}
+		asOf = parsed
+	}
+
+	balance, err := h.repo.GetBalance(id, userID, asOf)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Account not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    balance,
+	})
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/alerts"
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AlertHandler struct {
+	repo *repository.BudgetAlertRepository
+}
+
+func NewAlertHandler(repo *repository.BudgetAlertRepository) *AlertHandler {
+	return &AlertHandler{repo: repo}
+}
+
+// checkAlerts evaluates the budget alerts of the months of the given expense
+// dates. Failures are only logged: the expenses were saved anyway.
+func checkAlerts(evaluator *alerts.Evaluator, userID uuid.UUID, dates []time.Time) {
+	if len(dates) == 0 {
+		return
+	}
+
+	if _, err := evaluator.Check(userID, dates...); err != nil {
+		log.Printf("Budget alert check failed for user %s: %v", userID, err)
+	}
+}
+
+// expenseDates returns the dates of the expenses among the transactions.
+func expenseDates(transactions []models.Transaction) []time.Time {
+	var dates []time.Time
+	for _, transaction := range transactions {
+		if transaction.Type == "expense" {
+			dates = append(dates, transaction.Date)
+		}
+	}
+	return dates
+}
+
+// GetAll returns the budget alerts, newest first.
+func (h *AlertHandler) GetAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var filters models.BudgetAlertFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	alertList, err := h.repo.GetAll(userID, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve alerts",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if alertList == nil {
+		alertList = []models.BudgetAlert{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    alertList,
+	})
+}
+
+func (h *AlertHandler) MarkRead(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid alert ID",
+		})
+		return
+	}
+
+	if err := h.repo.MarkRead(id, userID); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Alert not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Alert marked as read",
+	})
+}
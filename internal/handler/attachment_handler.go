@@ -0,0 +1,373 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/Gildaciolopes/fintrack-api/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// allowedAttachmentTypes are the content types accepted for attachments, as
+// detected from the file content rather than the name or the client header.
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+type AttachmentHandler struct {
+	repo            *repository.AttachmentRepository
+	transactionRepo *repository.TransactionRepository
+	store           storage.Storage
+	maxSize         int64
+}
+
+func NewAttachmentHandler(
+	repo *repository.AttachmentRepository,
+	transactionRepo *repository.TransactionRepository,
+	store storage.Storage,
+	maxSize int64,
+) *AttachmentHandler {
+	return &AttachmentHandler{repo: repo, transactionRepo: transactionRepo, store: store, maxSize: maxSize}
+}
+
+// transaction resolves the :id parameter to a transaction of the user,
+// writing the error response and returning false when it cannot.
+func (h *AttachmentHandler) transaction(c *gin.Context, userID uuid.UUID) (uuid.UUID, bool) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid transaction ID",
+		})
+		return uuid.Nil, false
+	}
+
+	if _, err := h.transactionRepo.GetByID(transactionID, userID); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Transaction not found",
+			Message: err.Error(),
+		})
+		return uuid.Nil, false
+	}
+
+	return transactionID, true
+}
+
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	transactionID, ok := h.transaction(c, userID)
+	if !ok {
+		return
+	}
+
+	tooLarge := models.ErrorResponse{
+		Success: false,
+		Error:   fmt.Sprintf("File too large (max %d MB)", h.maxSize>>20),
+	}
+
+	// Leave room for the multipart envelope around the file.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "File is required",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if fileHeader.Size > h.maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+		return
+	}
+
+	if fileHeader.Size == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "File is empty",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read file",
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read file",
+			Message: err.Error(),
+		})
+		return
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !allowedAttachmentTypes[contentType] {
+		c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Success: false,
+			Error:   "Unsupported file type",
+			Message: fmt.Sprintf("detected %s; allowed types are JPEG, PNG, GIF, WebP and PDF", contentType),
+		})
+		return
+	}
+
+	attachment := &models.Attachment{
+		ID:            uuid.New(),
+		UserID:        userID,
+		TransactionID: transactionID,
+		Filename:      attachmentFilename(fileHeader.Filename),
+		ContentType:   contentType,
+		Size:          fileHeader.Size,
+	}
+	attachment.StorageKey = fmt.Sprintf("%s/%s/%s", userID, transactionID, attachment.ID)
+
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := h.store.Put(c.Request.Context(), attachment.StorageKey, body, attachment.Size, contentType); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to store attachment",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(attachment); err != nil {
+		if err := h.store.Delete(c.Request.Context(), attachment.StorageKey); err != nil {
+			log.Printf("attachments: failed to remove %s: %v", attachment.StorageKey, err)
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to create attachment",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Attachment uploaded successfully",
+		Data:    attachment,
+	})
+}
+
+func (h *AttachmentHandler) GetAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	transactionID, ok := h.transaction(c, userID)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.repo.GetByTransaction(transactionID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to fetch attachments",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    attachments,
+	})
+}
+
+// Download streams the attachment content. Browsers display it inline
+// unless download=true is given.
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	attachment, ok := h.attachment(c, userID)
+	if !ok {
+		return
+	}
+
+	reader, err := h.store.Get(c.Request.Context(), attachment.StorageKey)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read attachment",
+			Message: err.Error(),
+		})
+		return
+	}
+	defer reader.Close()
+
+	disposition := "inline"
+	if c.Query("download") == "true" {
+		disposition = "attachment"
+	}
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, reader, map[string]string{
+		"Content-Disposition": mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}),
+	})
+}
+
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	attachment, ok := h.attachment(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(attachment.ID, attachment.TransactionID, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete attachment",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	removeAttachmentFiles(c, h.store, []models.Attachment{*attachment})
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Attachment deleted successfully",
+	})
+}
+
+// attachment resolves the :id and :attachmentId parameters, writing the
+// error response and returning false when they do not match an attachment
+// of the user.
+func (h *AttachmentHandler) attachment(c *gin.Context, userID uuid.UUID) (*models.Attachment, bool) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid transaction ID",
+		})
+		return nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid attachment ID",
+		})
+		return nil, false
+	}
+
+	attachment, err := h.repo.GetByID(id, transactionID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Attachment not found",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	return attachment, true
+}
+
+// removeAttachmentFiles deletes the stored files of attachments whose rows
+// are already gone. Failures only leave an orphaned file behind, so they are
+// logged rather than reported to the client.
+func removeAttachmentFiles(c *gin.Context, store storage.Storage, attachments []models.Attachment) {
+	for _, attachment := range attachments {
+		err := store.Delete(c.Request.Context(), attachment.StorageKey)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("attachments: failed to remove %s: %v", attachment.StorageKey, err)
+		}
+	}
+}
+
+// attachmentFilename keeps the base name of the uploaded file without
+// control characters, falling back to "attachment".
+func attachmentFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+
+	return name
+}
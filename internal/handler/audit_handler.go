@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	repo            *repository.AuditRepository
+	transactionRepo *repository.TransactionRepository
+}
+
+func NewAuditHandler(repo *repository.AuditRepository, transactionRepo *repository.TransactionRepository) *AuditHandler {
+	return &AuditHandler{repo: repo, transactionRepo: transactionRepo}
+}
+
+// TransactionHistory returns every change made to a transaction, newest
+// first. The history outlives the transaction, so it is still returned once
+// the transaction was deleted.
+func (h *AuditHandler) TransactionHistory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid transaction ID",
+		})
+		return
+	}
+
+	entries, err := h.repo.GetByEntity(userID, models.AuditTransaction, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve transaction history",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Transactions created before the audit log have no history yet.
+	if len(entries) == 0 {
+		if _, err := h.transactionRepo.GetByID(id, userID); err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error:   "Transaction not found",
+			})
+			return
+		}
+		entries = []models.AuditEntry{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    entries,
+	})
+}
+
+// Activity returns the changes made to all of the user's records, newest
+// first, paged with the after cursor.
+func (h *AuditHandler) Activity(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var filters models.AuditFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if filters.Limit == 0 {
+		filters.Limit = 20
+	}
+
+	entries, hasMore, err := h.repo.GetFeed(userID, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve activity",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+
+	response := models.PaginatedResponse{
+		Success: true,
+		Data:    entries,
+		Limit:   filters.Limit,
+	}
+
+	if hasMore {
+		response.NextCursor = strconv.FormatInt(entries[len(entries)-1].ID, 10)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
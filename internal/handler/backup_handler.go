@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/backup"
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/Gildaciolopes/fintrack-api/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// maxBackupFileSize limits uploaded backup archives to 50 MB.
+const maxBackupFileSize = 50 << 20
+
+type BackupHandler struct {
+	backupRepo        *repository.BackupRepository
+	settingsRepo      *repository.SettingsRepository
+	categoryRepo      *repository.CategoryRepository
+	accountRepo       *repository.AccountRepository
+	tagRepo           *repository.TagRepository
+	payeeRepo         *repository.PayeeRepository
+	recurringRuleRepo *repository.RecurringRuleRepository
+	ruleRepo          *repository.CategorizationRuleRepository
+	transactionRepo   *repository.TransactionRepository
+	budgetRepo        *repository.BudgetRepository
+	templateRepo      *repository.BudgetTemplateRepository
+	goalRepo          *repository.GoalRepository
+	store             storage.Storage
+}
+
+func NewBackupHandler(
+	backupRepo *repository.BackupRepository,
+	settingsRepo *repository.SettingsRepository,
+	categoryRepo *repository.CategoryRepository,
+	accountRepo *repository.AccountRepository,
+	tagRepo *repository.TagRepository,
+	payeeRepo *repository.PayeeRepository,
+	recurringRuleRepo *repository.RecurringRuleRepository,
+	ruleRepo *repository.CategorizationRuleRepository,
+	transactionRepo *repository.TransactionRepository,
+	budgetRepo *repository.BudgetRepository,
+	templateRepo *repository.BudgetTemplateRepository,
+	goalRepo *repository.GoalRepository,
+	store storage.Storage,
+) *BackupHandler {
+	return &BackupHandler{
+		backupRepo:        backupRepo,
+		settingsRepo:      settingsRepo,
+		categoryRepo:      categoryRepo,
+		accountRepo:       accountRepo,
+		tagRepo:           tagRepo,
+		payeeRepo:         payeeRepo,
+		recurringRuleRepo: recurringRuleRepo,
+		ruleRepo:          ruleRepo,
+		transactionRepo:   transactionRepo,
+		budgetRepo:        budgetRepo,
+		templateRepo:      templateRepo,
+		goalRepo:          goalRepo,
+		store:             store,
+	}
+}
+
+// Export streams a ZIP archive with everything the user owns. Transactions
+// are written as they are read; the other entities are loaded up front so
+// failures can still be reported as JSON.
+func (h *BackupHandler) Export(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	data := &models.BackupData{}
+	err = func() (err error) {
+		if data.Settings, err = h.settingsRepo.Get(userID); err != nil {
+			return err
+		}
+		if data.Categories, err = h.categoryRepo.GetAll(userID, ""); err != nil {
+			return err
+		}
+		// Categories in the trash are kept, with deleted_at, since
+		// transactions and rules may still point at them.
+		deleted, err := h.categoryRepo.GetDeleted(userID)
+		if err != nil {
+			return err
+		}
+		data.Categories = append(data.Categories, deleted...)
+		if data.Accounts, err = h.accountRepo.GetAll(userID, true); err != nil {
+			return err
+		}
+		if data.Tags, err = h.tagRepo.GetAll(userID); err != nil {
+			return err
+		}
+		if data.Payees, err = h.payeeRepo.GetAll(userID); err != nil {
+			return err
+		}
+		if data.RecurringRules, err = h.recurringRuleRepo.GetAll(userID); err != nil {
+			return err
+		}
+		if data.CategorizationRules, err = h.ruleRepo.GetAll(userID); err != nil {
+			return err
+		}
+		if data.Budgets, err = h.budgetRepo.GetAll(userID, nil); err != nil {
+			return err
+		}
+		if data.BudgetTemplates, err = h.templateRepo.GetAll(userID); err != nil {
+			return err
+		}
+		data.Goals, err = h.goalRepo.GetAll(userID, "")
+		return err
+	}()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to export backup",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	for i := range data.Budgets {
+		data.Budgets[i].Category = nil
+	}
+
+	filename := fmt.Sprintf("fintrack-backup-%s.zip", time.Now().Format("20060102"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	writer := backup.NewWriter(c.Writer)
+	err = func() error {
+		entities := []struct {
+			name  string
+			value interface{}
+		}{
+			{backup.EntitySettings, data.Settings},
+			{backup.EntityCategories, data.Categories},
+			{backup.EntityAccounts, data.Accounts},
+			{backup.EntityTags, data.Tags},
+			{backup.EntityPayees, data.Payees},
+			{backup.EntityRecurringRules, data.RecurringRules},
+			{backup.EntityCategorizationRules, data.CategorizationRules},
+			{backup.EntityBudgets, data.Budgets},
+			{backup.EntityBudgetTemplates, data.BudgetTemplates},
+			{backup.EntityGoals, data.Goals},
+		}
+		for _, entity := range entities {
+			if err := writer.Add(entity.name, entity.value); err != nil {
+				return err
+			}
+		}
+
+		err := writer.Stream(backup.EntityTransactions, func(add func(interface{}) error) error {
+			return h.transactionRepo.Export(userID, models.TransactionFilters{}, func(transaction *models.Transaction) error {
+				transaction.Category = nil
+				for i := range transaction.Splits {
+					transaction.Splits[i].Category = nil
+				}
+				return add(transaction)
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		return writer.Close()
+	}()
+
+	// Headers are already sent: the best we can do is cut the stream short.
+	if err != nil {
+		log.Printf("Backup export failed for user %s: %v", userID, err)
+		c.Abort()
+	}
+}
+
+// Restore re-creates the records of an uploaded backup archive with new IDs,
+// in a single DB transaction, after checking the references between them.
+func (h *BackupHandler) Restore(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.RestoreBackupRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Backup file is required",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if fileHeader.Size > maxBackupFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Success: false,
+			Error:   "File too large (max 50 MB)",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read file",
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	manifest, data, err := backup.Read(file, fileHeader.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid backup file",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := backup.Validate(data); err != nil {
+		var validationErr *backup.ValidationError
+		message := err.Error()
+		if errors.As(err, &validationErr) {
+			message = strings.Join(validationErr.Problems, "; ")
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid backup data",
+			Message: message,
+		})
+		return
+	}
+
+	counts, storageKeys, err := h.backupRepo.WithAudit(middleware.GetAuditMeta(c)).Restore(userID, data, req.Replace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to restore backup",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// The replaced attachments' rows are gone: their files are only orphans.
+	for _, key := range storageKeys {
+		err := h.store.Delete(c.Request.Context(), key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("backup: failed to remove attachment %s: %v", key, err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Backup restored successfully",
+		Data: models.BackupManifest{
+			Format:    manifest.Format,
+			Version:   manifest.Version,
+			CreatedAt: manifest.CreatedAt,
+			Entities:  counts,
+		},
+	})
+}
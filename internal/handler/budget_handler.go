@@ -12,11 +12,12 @@ import (
 )
  
 type BudgetHandler struct {
-	repo *repository.BudgetRepository
+	repo         *repository.BudgetRepository
+	templateRepo *repository.BudgetTemplateRepository
 }
  
-func NewBudgetHandler(repo *repository.BudgetRepository) *BudgetHandler {
-	return &BudgetHandler{repo: repo}
+func NewBudgetHandler(repo *repository.BudgetRepository, templateRepo *repository.BudgetTemplateRepository) *BudgetHandler {
+	return &BudgetHandler{repo: repo, templateRepo: templateRepo}
 }
  
 func (h *BudgetHandler) Create(c *gin.Context) {
@@ -40,13 +41,15 @@ func (h *BudgetHandler) Create(c *gin.Context) {
 	}
 
 	budget := &models.Budget{
-		UserID:     userID,
-		CategoryID: req.CategoryID,
-		Amount:     req.Amount,
-		Month:      req.Month,
+		UserID:          userID,
+		CategoryID:      req.CategoryID,
+		Amount:          req.Amount,
+		Month:           req.Month,
+		Rollover:        req.Rollover,
+		AlertThresholds: req.AlertThresholds,
 	}
 
-	if err := h.repo.Create(budget); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(budget); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to create budget",
@@ -213,7 +216,7 @@ func (h *BudgetHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Update(id, userID, req.Amount, req.Month); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, req.Amount, req.Month, req.Rollover, req.AlertThresholds); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to update budget",
@@ -247,7 +250,7 @@ func (h *BudgetHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(id, userID); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to delete budget",
@@ -261,3 +264,125 @@ func (h *BudgetHandler) Delete(c *gin.Context) {
 		Message: "Budget deleted successfully",
 	})
 }
+ 
+// Copy creates the budgets of a month from the ones of another month.
+func (h *BudgetHandler) Copy(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.CopyBudgetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	lines, err := h.repo.GetLines(userID, req.FromMonth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve budgets",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.repo.WithAudit(middleware.GetAuditMeta(c)).ApplyLines(userID, req.ToMonth, lines, req.OnConflict)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to copy budgets",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Budgets copied successfully",
+		Data:    result,
+	})
+}
+ 
+// Generate creates the budgets of a month, next month by default, from a
+// template or from the average spend of the previous months.
+func (h *BudgetHandler) Generate(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.GenerateBudgetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	if req.Month != nil {
+		month = *req.Month
+	}
+
+	var lines []models.BudgetLine
+	if req.Source == models.BudgetSourceTemplate {
+		template, err := h.templateRepo.GetByID(*req.TemplateID, userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error:   "Budget template not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		lines = template.Items
+	} else {
+		months := req.Months
+		if months == 0 {
+			months = 1
+		}
+
+		lines, err = h.repo.SpendAverage(userID, month, months)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to compute average spend",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	result, err := h.repo.WithAudit(middleware.GetAuditMeta(c)).ApplyLines(userID, month, lines, req.OnConflict)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to generate budgets",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Budgets generated successfully",
+		Data:    result,
+	})
+}
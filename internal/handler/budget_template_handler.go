@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BudgetTemplateHandler struct {
+	repo       *repository.BudgetTemplateRepository
+	budgetRepo *repository.BudgetRepository
+}
+
+func NewBudgetTemplateHandler(
+	repo *repository.BudgetTemplateRepository,
+	budgetRepo *repository.BudgetRepository,
+) *BudgetTemplateHandler {
+	return &BudgetTemplateHandler{
+		repo:       repo,
+		budgetRepo: budgetRepo,
+	}
+}
+
+func (h *BudgetTemplateHandler) Create(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateBudgetTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	template := &models.BudgetTemplate{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+		Items:  req.Items,
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(template); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to create budget template",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Budget template created successfully",
+		Data:    template,
+	})
+}
+
+func (h *BudgetTemplateHandler) GetAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	templates, err := h.repo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve budget templates",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if templates == nil {
+		templates = []models.BudgetTemplate{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    templates,
+	})
+}
+
+func (h *BudgetTemplateHandler) GetByID(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid budget template ID",
+		})
+		return
+	}
+
+	template, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Budget template not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    template,
+	})
+}
+
+func (h *BudgetTemplateHandler) Update(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid budget template ID",
+		})
+		return
+	}
+
+	var req models.UpdateBudgetTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != "" {
+		updates["name"] = strings.TrimSpace(req.Name)
+	}
+	if req.Items != nil {
+		updates["items"] = req.Items
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to update budget template",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Budget template updated successfully",
+	})
+}
+
+func (h *BudgetTemplateHandler) Delete(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid budget template ID",
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete budget template",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Budget template deleted successfully",
+	})
+}
+
+// Apply creates the template's budgets in the given month.
+func (h *BudgetTemplateHandler) Apply(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid budget template ID",
+		})
+		return
+	}
+
+	var req models.ApplyBudgetTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	template, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Budget template not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.budgetRepo.WithAudit(middleware.GetAuditMeta(c)).ApplyLines(userID, req.Month, template.Items, req.OnConflict)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to apply budget template",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Budget template applied successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/payees"
+	"github.com/Gildaciolopes/fintrack-api/internal/rules"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// bulkResult builds the report of a bulk operation from the error of each
+// item (nil when it succeeded) and the status given to successful items.
+func bulkResult(ids []uuid.UUID, errs []error, status string) models.BulkResult {
+	result := models.BulkResult{Total: len(errs), Items: make([]models.BulkItemResult, len(errs))}
+	for _, err := range errs {
+		if err != nil {
+			result.Failed++
+		}
+	}
+
+	for i, err := range errs {
+		item := models.BulkItemResult{Index: i, Status: status}
+		if ids != nil && ids[i] != uuid.Nil {
+			id := ids[i]
+			item.ID = &id
+		}
+		switch {
+		case err != nil:
+			item.Status = models.BulkStatusFailed
+			item.Error = err.Error()
+		case result.Failed > 0:
+			item.Status = models.BulkStatusSkipped
+		default:
+			result.Succeeded++
+		}
+		result.Items[i] = item
+	}
+
+	return result
+}
+
+// respondBulk writes the report of a bulk operation: successStatus when every
+// item succeeded, 400 with the failures otherwise.
+func respondBulk(c *gin.Context, successStatus int, result models.BulkResult, message string) {
+	if result.Failed > 0 {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Error:   "Bulk operation failed",
+			Message: fmt.Sprintf("%d of %d items failed; no changes were saved", result.Failed, result.Total),
+			Data:    result,
+		})
+		return
+	}
+
+	c.JSON(successStatus, models.Response{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// BulkCreate creates up to models.MaxBulkItems transactions at once, running
+// the categorization rules and payee matching on each like Create, without
+// the duplicate check. Either all of them are created or none.
+func (h *TransactionHandler) BulkCreate(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.BulkCreateTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ruleList, err := h.ruleRepo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve categorization rules",
+			Message: err.Error(),
+		})
+		return
+	}
+	engine := rules.NewEngine(ruleList)
+
+	payeeList, err := h.payeeRepo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve payees",
+			Message: err.Error(),
+		})
+		return
+	}
+	matcher := payees.NewMatcher(payeeList)
+	ownPayees := make(map[uuid.UUID]bool, len(payeeList))
+	for _, payee := range payeeList {
+		ownPayees[payee.ID] = true
+	}
+
+	accountList, err := h.accountRepo.GetAll(userID, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve accounts",
+			Message: err.Error(),
+		})
+		return
+	}
+	ownAccounts := make(map[uuid.UUID]bool, len(accountList))
+	for _, account := range accountList {
+		ownAccounts[account.ID] = true
+	}
+
+	// Items are validated one by one so each failure is reported with its
+	// position instead of rejecting the request as a whole.
+	transactions := make([]models.Transaction, len(req.Transactions))
+	errs := make([]error, len(req.Transactions))
+	invalid := false
+	for i, item := range req.Transactions {
+		if errs[i] = binding.Validator.ValidateStruct(&item); errs[i] != nil {
+			invalid = true
+			continue
+		}
+
+		splits, err := buildSplits(item.Amount, item.Splits)
+		if err != nil {
+			errs[i] = err
+			invalid = true
+			continue
+		}
+
+		if item.AccountID != nil && !ownAccounts[*item.AccountID] {
+			errs[i] = fmt.Errorf("account not found")
+			invalid = true
+			continue
+		}
+
+		if item.PayeeID != nil && !ownPayees[*item.PayeeID] {
+			errs[i] = fmt.Errorf("payee not found")
+			invalid = true
+			continue
+		}
+
+		transaction := models.Transaction{
+			UserID:      userID,
+			CategoryID:  item.CategoryID,
+			AccountID:   item.AccountID,
+			PayeeID:     item.PayeeID,
+			Type:        item.Type,
+			Amount:      item.Amount,
+			Currency:    item.Currency,
+			Description: item.Description,
+			Date:        item.Date,
+			Splits:      splits,
+		}
+		for _, tagID := range item.TagIDs {
+			transaction.Tags = append(transaction.Tags, models.Tag{ID: tagID})
+		}
+
+		engine.Apply(&transaction, false)
+		matcher.Apply(&transaction, false)
+		transactions[i] = transaction
+	}
+
+	if !invalid {
+		errs, err = h.repo.WithAudit(middleware.GetAuditMeta(c)).BulkCreate(transactions)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to create transactions",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ids := make([]uuid.UUID, len(transactions))
+	for i := range transactions {
+		ids[i] = transactions[i].ID
+	}
+
+	result := bulkResult(ids, errs, models.BulkStatusCreated)
+	if result.Failed > 0 {
+		// Nothing was saved, so the IDs assigned on insert mean nothing.
+		for i := range result.Items {
+			result.Items[i].ID = nil
+		}
+	} else {
+		checkAlerts(h.alertEvaluator, userID, expenseDates(transactions))
+	}
+
+	respondBulk(c, http.StatusCreated, result, fmt.Sprintf("%d transactions created successfully", result.Succeeded))
+}
+
+// bulkSelection returns the IDs listed in the request, without repeats, or,
+// when there are none, the ones matching the query filters, writing the error
+// response and returning false when the selection is invalid or too large.
+func (h *TransactionHandler) bulkSelection(c *gin.Context, userID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, bool) {
+	if len(ids) > 0 {
+		seen := make(map[uuid.UUID]bool, len(ids))
+		unique := make([]uuid.UUID, 0, len(ids))
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				unique = append(unique, id)
+			}
+		}
+		return unique, true
+	}
+
+	var filters models.TransactionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	// An empty selection would otherwise mean every transaction: paging and
+	// sorting parameters alone do not count, only all=true does.
+	if !filters.HasFilter() && c.Query("all") != "true" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: "select the transactions with ids, list filters or all=true",
+		})
+		return nil, false
+	}
+
+	ids, err := h.repo.MatchingIDs(userID, filters, models.MaxBulkItems+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve transactions",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	if len(ids) > models.MaxBulkItems {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Too many transactions",
+			Message: fmt.Sprintf("the filters match more than %d transactions; narrow them down", models.MaxBulkItems),
+		})
+		return nil, false
+	}
+
+	return ids, true
+}
+
+// BulkUpdate applies the same changes to the transactions listed in the body
+// or matching the query filters. Either all of them are updated or none.
+func (h *TransactionHandler) BulkUpdate(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.BulkUpdateTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !req.HasChanges() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: "no changes requested",
+		})
+		return
+	}
+
+	ids, ok := h.bulkSelection(c, userID, req.IDs)
+	if !ok {
+		return
+	}
+
+	errs, err := h.repo.WithAudit(middleware.GetAuditMeta(c)).BulkUpdate(userID, ids, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to update transactions",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result := bulkResult(ids, errs, models.BulkStatusUpdated)
+	if result.Failed == 0 {
+		months, err := h.repo.ExpenseMonths(userID, ids)
+		if err != nil {
+			log.Printf("Budget alert check failed for user %s: %v", userID, err)
+		}
+		checkAlerts(h.alertEvaluator, userID, months)
+	}
+	respondBulk(c, http.StatusOK, result, fmt.Sprintf("%d transactions updated successfully", result.Succeeded))
+}
+
+// BulkDelete moves the transactions listed in the body or matching the query
+// filters to the trash. Either all of them are deleted or none.
+func (h *TransactionHandler) BulkDelete(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.BulkDeleteTransactionsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid request data",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ids, ok := h.bulkSelection(c, userID, req.IDs)
+	if !ok {
+		return
+	}
+
+	errs, err := h.repo.WithAudit(middleware.GetAuditMeta(c)).BulkDelete(userID, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete transactions",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result := bulkResult(ids, errs, models.BulkStatusDeleted)
+	respondBulk(c, http.StatusOK, result, fmt.Sprintf("%d transactions deleted successfully", result.Succeeded))
+}
@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/Gildaciolopes/fintrack-api/internal/rules"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CategorizationRuleHandler struct {
+	repo            *repository.CategorizationRuleRepository
+	transactionRepo *repository.TransactionRepository
+}
+
+func NewCategorizationRuleHandler(
+	repo *repository.CategorizationRuleRepository,
+	transactionRepo *repository.TransactionRepository,
+) *CategorizationRuleHandler {
+	return &CategorizationRuleHandler{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// validateRule checks the parts of a rule the binding tags cannot.
+func validateRule(rule *models.CategorizationRule) string {
+	pattern := ""
+	if rule.Pattern != nil {
+		pattern = *rule.Pattern
+	}
+	if err := rules.ValidatePattern(rule.MatchType, pattern); err != nil {
+		return err.Error()
+	}
+
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return "min_amount must not be greater than max_amount"
+	}
+
+	if rule.SetCategoryID == nil && len(rule.SetTagIDs) == 0 && rule.SetDescription == nil {
+		return "a rule must set a category, tags or a description"
+	}
+
+	return ""
+}
+
+func (h *CategorizationRuleHandler) Create(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateCategorizationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	matchType := req.MatchType
+	if matchType == "" {
+		matchType = rules.MatchContains
+	}
+
+	rule := &models.CategorizationRule{
+		UserID:          userID,
+		Name:            req.Name,
+		Priority:        req.Priority,
+		MatchType:       matchType,
+		Pattern:         req.Pattern,
+		TransactionType: req.TransactionType,
+		MinAmount:       req.MinAmount,
+		MaxAmount:       req.MaxAmount,
+		SetCategoryID:   req.SetCategoryID,
+		SetTagIDs:       req.SetTagIDs,
+		SetDescription:  req.SetDescription,
+	}
+
+	if message := validateRule(rule); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: message,
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to create categorization rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Categorization rule created successfully",
+		Data:    rule,
+	})
+}
+
+func (h *CategorizationRuleHandler) GetAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ruleList, err := h.repo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve categorization rules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if ruleList == nil {
+		ruleList = []models.CategorizationRule{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    ruleList,
+	})
+}
+
+func (h *CategorizationRuleHandler) GetByID(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid categorization rule ID",
+		})
+		return
+	}
+
+	rule, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Categorization rule not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    rule,
+	})
+}
+
+func (h *CategorizationRuleHandler) Update(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid categorization rule ID",
+		})
+		return
+	}
+
+	var req models.UpdateCategorizationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Categorization rule not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// An empty pattern or set_description clears it.
+	updates := make(map[string]interface{})
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+	if req.MatchType != "" {
+		updates["match_type"] = req.MatchType
+		rule.MatchType = req.MatchType
+	}
+	if req.Pattern != nil {
+		rule.Pattern = req.Pattern
+		if *req.Pattern == "" {
+			rule.Pattern = nil
+		}
+		updates["pattern"] = rule.Pattern
+	}
+	if req.TransactionType != nil {
+		updates["transaction_type"] = req.TransactionType
+	}
+	if req.MinAmount != nil {
+		updates["min_amount"] = *req.MinAmount
+		rule.MinAmount = req.MinAmount
+	}
+	if req.MaxAmount != nil {
+		updates["max_amount"] = *req.MaxAmount
+		rule.MaxAmount = req.MaxAmount
+	}
+	if req.SetCategoryID != nil {
+		updates["set_category_id"] = req.SetCategoryID
+		rule.SetCategoryID = req.SetCategoryID
+	}
+	if req.SetTagIDs != nil {
+		updates["set_tag_ids"] = req.SetTagIDs
+		rule.SetTagIDs = req.SetTagIDs
+	}
+	if req.SetDescription != nil {
+		rule.SetDescription = req.SetDescription
+		if *req.SetDescription == "" {
+			rule.SetDescription = nil
+		}
+		updates["set_description"] = rule.SetDescription
+	}
+
+	if message := validateRule(rule); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: message,
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to update categorization rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Categorization rule updated successfully",
+	})
+}
+
+func (h *CategorizationRuleHandler) Delete(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid categorization rule ID",
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete categorization rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Categorization rule deleted successfully",
+	})
+}
+
+// Apply runs the active rules over the existing transactions matching the
+// list filters (query parameters) and saves the changes in one DB
+// transaction.
+func (h *CategorizationRuleHandler) Apply(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var filters models.TransactionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req models.ApplyCategorizationRulesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid request data",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ruleList, err := h.repo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve categorization rules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	engine := rules.NewEngine(ruleList)
+	result := models.ApplyCategorizationRulesResult{
+		DryRun:  req.DryRun,
+		Changes: []models.RuleChange{},
+	}
+
+	err = h.transactionRepo.Export(userID, filters, func(transaction *models.Transaction) error {
+		if engine.Match(transaction) != nil {
+			result.Matched++
+		}
+		if change := engine.Apply(transaction, req.Overwrite); change != nil {
+			result.Changes = append(result.Changes, *change)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to apply categorization rules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !req.DryRun && len(result.Changes) > 0 {
+		if err := h.transactionRepo.WithAudit(middleware.GetAuditMeta(c)).ApplyRuleChanges(userID, result.Changes); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to apply categorization rules",
+				Message: err.Error(),
+			})
+			return
+		}
+		result.Updated = len(result.Changes)
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    result,
+	})
+}
@@ -46,7 +46,7 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 		Icon:   req.Icon,
 	}
 
-	if err := h.repo.Create(category); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(category); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to create category",
@@ -172,7 +172,7 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		updates["icon"] = req.Icon
 	}
 
-	if err := h.repo.Update(id, userID, updates); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to update category",
@@ -206,7 +206,7 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(id, userID); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to delete category",
@@ -111,6 +111,110 @@ func (h *DashboardHandler) GetExpensesByCategory(c *gin.Context) {
 		Data:    expenses,
 	})
 }
+
+func (h *DashboardHandler) GetByTag(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	startDate := time.Now().AddDate(0, 0, -30)
+	endDate := time.Now()
+
+	if start := c.Query("start_date"); start != "" {
+		if parsed, err := time.Parse("2006-01-02", start); err == nil {
+			startDate = parsed
+		}
+	}
+
+	if end := c.Query("end_date"); end != "" {
+		if parsed, err := time.Parse("2006-01-02", end); err == nil {
+			endDate = parsed
+		}
+	}
+
+	summaries, err := h.dashboardRepo.GetTagSummary(userID, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve tag summary",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if summaries == nil {
+		summaries = []models.TagSummary{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    summaries,
+	})
+}
+
+// GetTopPayees ranks the payees by spend (sort_by=amount, default) or number
+// of transactions (sort_by=count) in the period. type=income ranks payers.
+func (h *DashboardHandler) GetTopPayees(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	startDate := time.Now().AddDate(0, 0, -30)
+	endDate := time.Now()
+
+	if start := c.Query("start_date"); start != "" {
+		if parsed, err := time.Parse("2006-01-02", start); err == nil {
+			startDate = parsed
+		}
+	}
+
+	if end := c.Query("end_date"); end != "" {
+		if parsed, err := time.Parse("2006-01-02", end); err == nil {
+			endDate = parsed
+		}
+	}
+
+	transactionType := "expense"
+	if c.Query("type") == "income" {
+		transactionType = "income"
+	}
+
+	limit := 10
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 50 {
+			limit = parsed
+		}
+	}
+
+	summaries, err := h.dashboardRepo.GetTopPayees(userID, startDate, endDate, transactionType, c.Query("sort_by") == "count", limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve top payees",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if summaries == nil {
+		summaries = []models.PayeeSummary{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    summaries,
+	})
+}
  
 func (h *DashboardHandler) GetMonthlyData(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
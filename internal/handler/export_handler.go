@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/exporter"
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ExportHandler struct {
+	transactionRepo *repository.TransactionRepository
+	accountRepo     *repository.AccountRepository
+}
+
+func NewExportHandler(
+	transactionRepo *repository.TransactionRepository,
+	accountRepo *repository.AccountRepository,
+) *ExportHandler {
+	return &ExportHandler{
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+	}
+}
+
+// ExportTransactions streams every transaction matching the list filters as
+// CSV, NDJSON or OFX (format query parameter, default csv).
+func (h *ExportHandler) ExportTransactions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var filters models.TransactionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", exporter.FormatCSV)
+	if format != exporter.FormatCSV && format != exporter.FormatNDJSON && format != exporter.FormatOFX {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid format (use csv, ndjson or ofx)",
+		})
+		return
+	}
+
+	accounts, err := h.accountRepo.GetAll(userID, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve accounts",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	options := exporter.Options{AccountNames: make(map[uuid.UUID]string, len(accounts))}
+	for _, account := range accounts {
+		options.AccountNames[account.ID] = account.Name
+	}
+
+	if format == exporter.FormatOFX {
+		statement, status, err := h.statement(userID, filters, accounts)
+		if err != nil {
+			message := "Failed to export transactions"
+			if status != http.StatusInternalServerError {
+				message = "Invalid OFX export"
+			}
+			c.JSON(status, models.ErrorResponse{
+				Success: false,
+				Error:   message,
+				Message: err.Error(),
+			})
+			return
+		}
+		options.Statement = *statement
+	}
+
+	filename := fmt.Sprintf("transactions-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Type", exporter.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	writer, err := exporter.NewWriter(format, c.Writer, options)
+	if err == nil {
+		err = h.transactionRepo.Export(userID, filters, writer.Write)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	// Headers are already sent: the best we can do is cut the stream short.
+	if err != nil {
+		log.Printf("Transaction export failed for user %s: %v", userID, err)
+		c.Abort()
+	}
+}
+
+// statement builds the OFX statement header: the filtered account over the
+// period of the exported transactions, with its balance at the end of it. An
+// OFX statement holds a single account and currency, so the export needs an
+// account_id filter and transactions in the account currency; otherwise the
+// returned status is a client error.
+func (h *ExportHandler) statement(userID uuid.UUID, filters models.TransactionFilters, accounts []models.Account) (*exporter.Statement, int, error) {
+	if filters.AccountID == nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("an OFX statement covers a single account: filter by account_id")
+	}
+
+	accountID, err := uuid.Parse(*filters.AccountID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid account_id")
+	}
+
+	statement := &exporter.Statement{AccountID: accountID.String()}
+	for _, account := range accounts {
+		if account.ID == accountID {
+			statement.Currency = account.Currency
+		}
+	}
+	if statement.Currency == "" {
+		return nil, http.StatusNotFound, fmt.Errorf("account not found")
+	}
+
+	currencies, err := h.transactionRepo.Currencies(userID, filters)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for _, currency := range currencies {
+		if currency != statement.Currency {
+			return nil, http.StatusBadRequest, fmt.Errorf(
+				"the transactions include amounts in %s, not in the account currency %s: use csv or ndjson",
+				strings.Join(currencies, ", "), statement.Currency,
+			)
+		}
+	}
+
+	first, last, err := h.transactionRepo.DateRange(userID, filters)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	statement.Start, statement.End = time.Now(), time.Now()
+	if first != nil {
+		statement.Start, statement.End = *first, *last
+	}
+	if filters.StartDate != nil {
+		statement.Start = *filters.StartDate
+	}
+	if filters.EndDate != nil {
+		statement.End = *filters.EndDate
+	}
+
+	balance, err := h.accountRepo.GetBalance(accountID, userID, statement.End)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	statement.Balance = balance.Balance
+
+	return statement, http.StatusOK, nil
+}
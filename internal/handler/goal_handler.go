@@ -46,7 +46,7 @@ func (h *GoalHandler) Create(c *gin.Context) {
 		Deadline:      req.Deadline,
 	}
 
-	if err := h.repo.Create(goal); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(goal); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to create goal",
@@ -175,7 +175,7 @@ func (h *GoalHandler) Update(c *gin.Context) {
 		updates["status"] = req.Status
 	}
 
-	if err := h.repo.Update(id, userID, updates); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to update goal",
@@ -209,7 +209,7 @@ func (h *GoalHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(id, userID); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to delete goal",
@@ -253,7 +253,7 @@ func (h *GoalHandler) Contribute(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Contribute(id, userID, req.Amount); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Contribute(id, userID, req.Amount); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to contribute to goal",
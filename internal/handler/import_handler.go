@@ -0,0 +1,385 @@
+package handler
+
+import (
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/alerts"
+	"github.com/Gildaciolopes/fintrack-api/internal/classifier"
+	"github.com/Gildaciolopes/fintrack-api/internal/duplicates"
+	"github.com/Gildaciolopes/fintrack-api/internal/importer"
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/payees"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/Gildaciolopes/fintrack-api/internal/rules"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxImportFileSize limits uploaded statement files to 5 MB.
+const maxImportFileSize = 5 << 20
+
+type ImportHandler struct {
+	transactionRepo *repository.TransactionRepository
+	accountRepo     *repository.AccountRepository
+	ruleRepo        *repository.CategorizationRuleRepository
+	payeeRepo       *repository.PayeeRepository
+	alertEvaluator  *alerts.Evaluator
+}
+
+func NewImportHandler(
+	transactionRepo *repository.TransactionRepository,
+	accountRepo *repository.AccountRepository,
+	ruleRepo *repository.CategorizationRuleRepository,
+	payeeRepo *repository.PayeeRepository,
+	alertEvaluator *alerts.Evaluator,
+) *ImportHandler {
+	return &ImportHandler{
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+		ruleRepo:        ruleRepo,
+		payeeRepo:       payeeRepo,
+		alertEvaluator:  alertEvaluator,
+	}
+}
+
+// ImportCSV parses an uploaded CSV statement with the mapping sent in the
+// form fields. With dry_run it only returns the preview; otherwise the valid
+// rows are stored in a single DB transaction.
+func (h *ImportHandler) ImportCSV(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var profile models.CSVImportProfile
+	if err := c.ShouldBind(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	file, ok := h.openFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	rows, err := importer.ParseCSV(file, profile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid CSV file",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.store(c, userID, profile.ImportOptions, rows)
+}
+
+// ImportOFX imports the transactions of an OFX/QFX statement. Entries whose
+// FITID was already imported are reported as duplicates and skipped.
+func (h *ImportHandler) ImportOFX(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.OFXImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	file, ok := h.openFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	rows, err := importer.ParseOFX(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid OFX file",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.store(c, userID, req.ImportOptions, rows)
+}
+
+// openFile opens the uploaded "file" field, writing the error response and
+// returning false when it is missing or too large.
+func (h *ImportHandler) openFile(c *gin.Context) (multipart.File, bool) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "File is required",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Success: false,
+			Error:   "File too large (max 5 MB)",
+		})
+		return nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read file",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	return file, true
+}
+
+// store classifies the parsed rows as new, duplicate, possible duplicate or
+// invalid, runs the categorization rules (and, with auto_categorize, the
+// classifier) on the new ones and, unless this is a dry run, saves them in a
+// single DB transaction.
+func (h *ImportHandler) store(c *gin.Context, userID uuid.UUID, options models.ImportOptions, rows []models.ImportRow) {
+	var accountID, categoryID *uuid.UUID
+	if options.AccountID != nil {
+		id := uuid.MustParse(*options.AccountID)
+		if _, err := h.accountRepo.GetByID(id, userID); err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error:   "Account not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		accountID = &id
+	}
+	if options.CategoryID != nil {
+		id := uuid.MustParse(*options.CategoryID)
+		categoryID = &id
+	}
+
+	var externalIDs []string
+	for _, row := range rows {
+		if row.Error == "" && row.ExternalID != "" {
+			externalIDs = append(externalIDs, row.ExternalID)
+		}
+	}
+
+	existing, err := h.transactionRepo.ExistingExternalIDs(userID, externalIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to check for duplicates",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ruleList, err := h.ruleRepo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve categorization rules",
+			Message: err.Error(),
+		})
+		return
+	}
+	engine := rules.NewEngine(ruleList)
+
+	payeeList, err := h.payeeRepo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve payees",
+			Message: err.Error(),
+		})
+		return
+	}
+	matcher := payees.NewMatcher(payeeList)
+
+	var model *classifier.Model
+	if options.AutoCategorize != nil {
+		if model, err = trainClassifier(h.transactionRepo, userID); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to train the category classifier",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	result := models.ImportResult{
+		Total:  len(rows),
+		DryRun: options.DryRun,
+		Rows:   rows,
+	}
+	if result.Rows == nil {
+		result.Rows = []models.ImportRow{}
+	}
+
+	// Rows without an external ID match (or from banks that do not send
+	// one) are checked against similar existing transactions instead.
+	var candidates []models.Transaction
+	if !options.AllowDuplicates {
+		var start, end time.Time
+		var amounts []models.Money
+		for _, row := range rows {
+			if row.Error != "" {
+				continue
+			}
+			if start.IsZero() || row.Date.Before(start) {
+				start = row.Date
+			}
+			if row.Date.After(end) {
+				end = row.Date
+			}
+			amounts = append(amounts, row.Amount)
+		}
+
+		candidates, err = h.transactionRepo.DuplicateCandidates(userID, start.Add(-duplicates.Window), end.Add(duplicates.Window), amounts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to check for duplicates",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	var transactions []models.Transaction
+	for i := range rows {
+		row := &rows[i]
+
+		switch {
+		case row.Error != "":
+			row.Status = models.ImportStatusInvalid
+			result.Invalid++
+			continue
+		case row.ExternalID != "" && existing[row.ExternalID]:
+			row.Status = models.ImportStatusDuplicate
+			result.Duplicate++
+			continue
+		}
+
+		currency := row.Currency
+		if options.Currency != "" {
+			currency = options.Currency
+		}
+
+		transaction := models.Transaction{
+			UserID:     userID,
+			CategoryID: categoryID,
+			AccountID:  accountID,
+			Type:       row.Type,
+			Amount:     row.Amount,
+			Currency:   currency,
+			Date:       row.Date,
+		}
+		if row.Description != "" {
+			description := row.Description
+			transaction.Description = &description
+		}
+		if row.ExternalID != "" {
+			externalID := row.ExternalID
+			transaction.ExternalID = &externalID
+		}
+
+		if !options.AllowDuplicates {
+			if ids := duplicates.Find(&transaction, candidates); len(ids) > 0 {
+				row.Status = models.ImportStatusPossibleDuplicate
+				row.DuplicateOf = ids
+				result.PossibleDuplicate++
+				continue
+			}
+		}
+
+		row.Status = models.ImportStatusNew
+		result.Valid++
+		if row.ExternalID != "" {
+			// Repeated entries in the same file are duplicates too.
+			existing[row.ExternalID] = true
+		}
+
+		if change := engine.Apply(&transaction, false); change != nil {
+			ruleID := change.RuleID
+			row.RuleID = &ruleID
+		}
+		matcher.Apply(&transaction, false)
+		row.PayeeID = transaction.PayeeID
+		if model != nil && transaction.CategoryID == nil {
+			suggestions := model.Suggest(row.Description, row.Type, row.Amount, 1)
+			if len(suggestions) > 0 && suggestions[0].Confidence >= *options.AutoCategorize {
+				categoryID, confidence := suggestions[0].CategoryID, suggestions[0].Confidence
+				transaction.CategoryID = &categoryID
+				row.SuggestedCategoryID = &categoryID
+				row.Confidence = &confidence
+			}
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if options.DryRun {
+		c.JSON(http.StatusOK, models.Response{
+			Success: true,
+			Data:    result,
+		})
+		return
+	}
+
+	if result.Invalid > 0 && !options.SkipInvalid {
+		c.JSON(http.StatusUnprocessableEntity, models.Response{
+			Success: false,
+			Error:   "Some rows are invalid (use skip_invalid to import the valid ones)",
+			Data:    result,
+		})
+		return
+	}
+
+	if len(transactions) > 0 {
+		if err := h.transactionRepo.WithAudit(middleware.GetAuditMeta(c)).CreateBatch(transactions); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to import transactions",
+				Message: err.Error(),
+			})
+			return
+		}
+		checkAlerts(h.alertEvaluator, userID, expenseDates(transactions))
+	}
+	result.Imported = len(transactions)
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Transactions imported successfully",
+		Data:    result,
+	})
+}
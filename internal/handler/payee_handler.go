@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/payees"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PayeeHandler struct {
+	repo            *repository.PayeeRepository
+	transactionRepo *repository.TransactionRepository
+}
+
+func NewPayeeHandler(
+	repo *repository.PayeeRepository,
+	transactionRepo *repository.TransactionRepository,
+) *PayeeHandler {
+	return &PayeeHandler{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// cleanAliases trims the aliases and rejects the ones that could never match
+// a description.
+func cleanAliases(aliases []string) ([]string, error) {
+	cleaned := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if payees.Normalize(alias) == "" {
+			return nil, fmt.Errorf("alias %q has no words to match", alias)
+		}
+		cleaned = append(cleaned, alias)
+	}
+	return cleaned, nil
+}
+
+func (h *PayeeHandler) Create(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.CreatePayeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	aliases, err := cleanAliases(req.Aliases)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payee := &models.Payee{
+		UserID:  userID,
+		Name:    strings.TrimSpace(req.Name),
+		Aliases: aliases,
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(payee); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to create payee",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Payee created successfully",
+		Data:    payee,
+	})
+}
+
+func (h *PayeeHandler) GetAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	payeeList, err := h.repo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve payees",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if payeeList == nil {
+		payeeList = []models.Payee{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    payeeList,
+	})
+}
+
+func (h *PayeeHandler) GetByID(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid payee ID",
+		})
+		return
+	}
+
+	payee, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Payee not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    payee,
+	})
+}
+
+func (h *PayeeHandler) Update(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid payee ID",
+		})
+		return
+	}
+
+	var req models.UpdatePayeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != "" {
+		updates["name"] = strings.TrimSpace(req.Name)
+	}
+	if req.Aliases != nil {
+		aliases, err := cleanAliases(req.Aliases)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid request data",
+				Message: err.Error(),
+			})
+			return
+		}
+		updates["aliases"] = aliases
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to update payee",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Payee updated successfully",
+	})
+}
+
+func (h *PayeeHandler) Delete(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid payee ID",
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete payee",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Payee deleted successfully",
+	})
+}
+
+// Apply links the existing transactions matching the list filters (query
+// parameters) to the payees whose name or aliases appear in their
+// descriptions.
+func (h *PayeeHandler) Apply(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var filters models.TransactionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req models.ApplyPayeesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid request data",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	payeeList, err := h.repo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve payees",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	matcher := payees.NewMatcher(payeeList)
+	result := models.ApplyPayeesResult{DryRun: req.DryRun}
+	changes := make(map[uuid.UUID]uuid.UUID)
+
+	err = h.transactionRepo.Export(userID, filters, func(transaction *models.Transaction) error {
+		if transaction.Description != nil && transaction.TransferID == nil && matcher.Match(*transaction.Description) != nil {
+			result.Matched++
+		}
+		if matcher.Apply(transaction, req.Overwrite) {
+			changes[transaction.ID] = *transaction.PayeeID
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to apply payees",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !req.DryRun && len(changes) > 0 {
+		if err := h.transactionRepo.WithAudit(middleware.GetAuditMeta(c)).SetPayees(userID, changes); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to apply payees",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	result.Linked = len(changes)
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    result,
+	})
+}
@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/recurring"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RecurringRuleHandler struct {
+	repo        *repository.RecurringRuleRepository
+	accountRepo *repository.AccountRepository
+}
+
+func NewRecurringRuleHandler(repo *repository.RecurringRuleRepository, accountRepo *repository.AccountRepository) *RecurringRuleHandler {
+	return &RecurringRuleHandler{repo: repo, accountRepo: accountRepo}
+}
+
+func (h *RecurringRuleHandler) Create(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateRecurringRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.EndDate != nil && req.EndDate.Before(req.StartDate) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: "end_date must not be before start_date",
+		})
+		return
+	}
+
+	if !ownsAccount(c, h.accountRepo, userID, req.AccountID) {
+		return
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	rule := &models.RecurringRule{
+		UserID:      userID,
+		Frequency:   req.Frequency,
+		Interval:    interval,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		DayOfMonth:  req.DayOfMonth,
+		CategoryID:  req.CategoryID,
+		AccountID:   req.AccountID,
+		Type:        req.Type,
+		Amount:      req.Amount,
+		Description: req.Description,
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to create recurring rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: "Recurring rule created successfully",
+		Data:    rule,
+	})
+}
+
+func (h *RecurringRuleHandler) GetAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	rules, err := h.repo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve recurring rules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if rules == nil {
+		rules = []models.RecurringRule{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    rules,
+	})
+}
+
+func (h *RecurringRuleHandler) GetByID(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid recurring rule ID",
+		})
+		return
+	}
+
+	rule, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Recurring rule not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    rule,
+	})
+}
+
+func (h *RecurringRuleHandler) Update(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid recurring rule ID",
+		})
+		return
+	}
+
+	var req models.UpdateRecurringRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !ownsAccount(c, h.accountRepo, userID, req.AccountID) {
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Frequency != "" {
+		updates["frequency"] = req.Frequency
+	}
+	if req.Interval > 0 {
+		updates["interval"] = req.Interval
+	}
+	if req.EndDate != nil {
+		updates["end_date"] = req.EndDate
+	}
+	if req.DayOfMonth != nil {
+		updates["day_of_month"] = req.DayOfMonth
+	}
+	if req.CategoryID != nil {
+		updates["category_id"] = req.CategoryID
+	}
+	if req.AccountID != nil {
+		updates["account_id"] = req.AccountID
+	}
+	if req.Type != "" {
+		updates["type"] = req.Type
+	}
+	if req.Amount > 0 {
+		updates["amount"] = req.Amount
+	}
+	if req.Description != nil {
+		updates["description"] = req.Description
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to update recurring rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Recurring rule updated successfully",
+	})
+}
+
+func (h *RecurringRuleHandler) Delete(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid recurring rule ID",
+		})
+		return
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete recurring rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Recurring rule deleted successfully",
+	})
+}
+
+// Preview lists the next occurrences of a rule, starting today.
+func (h *RecurringRuleHandler) Preview(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid recurring rule ID",
+		})
+		return
+	}
+
+	count := 5
+	if n := c.Query("count"); n != "" {
+		if parsed, err := strconv.Atoi(n); err == nil && parsed > 0 && parsed <= 50 {
+			count = parsed
+		}
+	}
+
+	rule, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Recurring rule not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	from := time.Now()
+	if rule.StartDate.After(from) {
+		from = rule.StartDate
+	}
+
+	occurrences := []models.RecurringOccurrence{}
+	for _, date := range recurring.Occurrences(*rule, from, from.AddDate(100, 0, 0), count) {
+		occurrences = append(occurrences, models.RecurringOccurrence{
+			Date:        date.Format("2006-01-02"),
+			Type:        rule.Type,
+			Amount:      rule.Amount,
+			Description: rule.Description,
+			CategoryID:  rule.CategoryID,
+			AccountID:   rule.AccountID,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    occurrences,
+	})
+}
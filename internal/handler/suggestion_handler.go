@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/classifier"
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// trainingSetSize is how many of the user's most recent categorized
+// transactions the category classifier learns from.
+const trainingSetSize = 5000
+
+// trainClassifier builds the user's category classifier from their history.
+func trainClassifier(repo *repository.TransactionRepository, userID uuid.UUID) (*classifier.Model, error) {
+	transactions, err := repo.TrainingSet(userID, trainingSetSize)
+	if err != nil {
+		return nil, err
+	}
+	return classifier.Train(transactions), nil
+}
+
+type SuggestionHandler struct {
+	transactionRepo *repository.TransactionRepository
+	categoryRepo    *repository.CategoryRepository
+}
+
+func NewSuggestionHandler(
+	transactionRepo *repository.TransactionRepository,
+	categoryRepo *repository.CategoryRepository,
+) *SuggestionHandler {
+	return &SuggestionHandler{
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+	}
+}
+
+// SuggestCategory ranks the user's categories for a new transaction by how
+// similar transactions were categorized before.
+func (h *SuggestionHandler) SuggestCategory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.SuggestCategoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 3
+	}
+
+	model, err := trainClassifier(h.transactionRepo, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to suggest a category",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	categories, err := h.categoryRepo.GetAll(userID, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve categories",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	byID := make(map[uuid.UUID]*models.Category, len(categories))
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
+	}
+
+	suggestions := []models.CategorySuggestion{}
+	for _, suggestion := range model.Suggest(req.Description, req.Type, req.Amount, limit) {
+		suggestions = append(suggestions, models.CategorySuggestion{
+			CategoryID: suggestion.CategoryID,
+			Category:   byID[suggestion.CategoryID],
+			Confidence: suggestion.Confidence,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    suggestions,
+	})
+}
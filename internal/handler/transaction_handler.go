@@ -1,21 +1,43 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/Gildaciolopes/fintrack-api/internal/alerts"
+	"github.com/Gildaciolopes/fintrack-api/internal/duplicates"
 	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
 	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/payees"
 	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/Gildaciolopes/fintrack-api/internal/rules"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type TransactionHandler struct {
-	repo *repository.TransactionRepository
+	repo           *repository.TransactionRepository
+	ruleRepo       *repository.CategorizationRuleRepository
+	payeeRepo      *repository.PayeeRepository
+	accountRepo    *repository.AccountRepository
+	alertEvaluator *alerts.Evaluator
 }
 
-func NewTransactionHandler(repo *repository.TransactionRepository) *TransactionHandler {
-	return &TransactionHandler{repo: repo}
+func NewTransactionHandler(
+	repo *repository.TransactionRepository,
+	ruleRepo *repository.CategorizationRuleRepository,
+	payeeRepo *repository.PayeeRepository,
+	accountRepo *repository.AccountRepository,
+	alertEvaluator *alerts.Evaluator,
+) *TransactionHandler {
+	return &TransactionHandler{
+		repo:           repo,
+		ruleRepo:       ruleRepo,
+		payeeRepo:      payeeRepo,
+		accountRepo:    accountRepo,
+		alertEvaluator: alertEvaluator,
+	}
 }
 
 func (h *TransactionHandler) Create(c *gin.Context) {
@@ -38,16 +60,95 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
+	splits, err := buildSplits(req.Amount, req.Splits)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !ownsAccount(c, h.accountRepo, userID, req.AccountID) || !h.ownsPayee(c, userID, req.PayeeID) {
+		return
+	}
+
 	transaction := &models.Transaction{
 		UserID:      userID,
 		CategoryID:  req.CategoryID,
+		AccountID:   req.AccountID,
+		PayeeID:     req.PayeeID,
 		Type:        req.Type,
 		Amount:      req.Amount,
+		Currency:    req.Currency,
 		Description: req.Description,
 		Date:        req.Date,
+		Splits:      splits,
+	}
+	for _, tagID := range req.TagIDs {
+		transaction.Tags = append(transaction.Tags, models.Tag{ID: tagID})
+	}
+
+	// Unless forced, refuse to create what looks like a transaction that
+	// already exists and return the candidates instead.
+	if c.Query("force") != "true" {
+		candidates, err := h.repo.DuplicateCandidates(
+			userID,
+			req.Date.Add(-duplicates.Window),
+			req.Date.Add(duplicates.Window),
+			[]models.Money{req.Amount},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Failed to check for duplicates",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		var matches []models.Transaction
+		for i := range candidates {
+			if duplicates.Similar(transaction, &candidates[i]) {
+				matches = append(matches, candidates[i])
+			}
+		}
+
+		if len(matches) > 0 {
+			c.JSON(http.StatusConflict, models.Response{
+				Success: false,
+				Error:   "Possible duplicate transaction",
+				Message: "A similar transaction already exists; send force=true to create it anyway",
+				Data:    matches,
+			})
+			return
+		}
+	}
+
+	ruleList, err := h.ruleRepo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve categorization rules",
+			Message: err.Error(),
+		})
+		return
 	}
+	rules.NewEngine(ruleList).Apply(transaction, false)
 
-	if err := h.repo.Create(transaction); err != nil {
+	payeeList, err := h.payeeRepo.GetAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve payees",
+			Message: err.Error(),
+		})
+		return
+	}
+	payees.NewMatcher(payeeList).Apply(transaction, false)
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Create(transaction); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to create transaction",
@@ -56,6 +157,8 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
+	checkAlerts(h.alertEvaluator, userID, expenseDates([]models.Transaction{*transaction}))
+
 	c.JSON(http.StatusCreated, models.Response{
 		Success: true,
 		Message: "Transaction created successfully",
@@ -83,7 +186,15 @@ func (h *TransactionHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	transactions, totalCount, err := h.repo.GetAll(userID, filters)
+	result, err := h.repo.GetAll(userID, filters)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -93,32 +204,41 @@ func (h *TransactionHandler) GetAll(c *gin.Context) {
 		return
 	}
 
+	transactions := result.Transactions
 	if transactions == nil {
 		transactions = []models.Transaction{}
 	}
 
-	page := filters.Page
-	if page == 0 {
-		page = 1
-	}
 	limit := filters.Limit
 	if limit == 0 {
 		limit = 20
 	}
 
-	totalPages := int(totalCount) / limit
-	if int(totalCount)%limit != 0 {
-		totalPages++
-	}
-
-	c.JSON(http.StatusOK, models.PaginatedResponse{
+	response := models.PaginatedResponse{
 		Success:    true,
 		Data:       transactions,
-		Page:       page,
 		Limit:      limit,
-		TotalCount: totalCount,
-		TotalPages: totalPages,
-	})
+		TotalCount: result.TotalCount,
+		NextCursor: result.NextCursor,
+		PrevCursor: result.PrevCursor,
+	}
+
+	if filters.After == nil && filters.Before == nil {
+		response.Page = filters.Page
+		if response.Page == 0 {
+			response.Page = 1
+		}
+	}
+
+	if result.TotalCount != nil {
+		totalPages := int(*result.TotalCount) / limit
+		if int(*result.TotalCount)%limit != 0 {
+			totalPages++
+		}
+		response.TotalPages = &totalPages
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *TransactionHandler) GetByID(c *gin.Context) {
@@ -185,16 +305,48 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.repo.GetByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Transaction not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if existing.TransferID != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Transfer legs cannot be edited individually",
+			Message: "Delete the transfer and create it again",
+		})
+		return
+	}
+
+	if !ownsAccount(c, h.accountRepo, userID, req.AccountID) || !h.ownsPayee(c, userID, req.PayeeID) {
+		return
+	}
+
 	updates := make(map[string]interface{})
 	if req.CategoryID != nil {
 		updates["category_id"] = req.CategoryID
 	}
+	if req.AccountID != nil {
+		updates["account_id"] = req.AccountID
+	}
+	if req.PayeeID != nil {
+		updates["payee_id"] = req.PayeeID
+	}
 	if req.Type != "" {
 		updates["type"] = req.Type
 	}
 	if req.Amount > 0 {
 		updates["amount"] = req.Amount
 	}
+	if req.Currency != "" {
+		updates["currency"] = req.Currency
+	}
 	if req.Description != nil {
 		updates["description"] = req.Description
 	}
@@ -202,7 +354,40 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 		updates["date"] = req.Date
 	}
 
-	if err := h.repo.Update(id, userID, updates); err != nil {
+	amount := existing.Amount
+	if req.Amount > 0 {
+		amount = req.Amount
+	}
+
+	var splits []models.TransactionSplit
+	if req.Splits != nil {
+		splits, err = buildSplits(amount, req.Splits)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid request data",
+				Message: err.Error(),
+			})
+			return
+		}
+		if splits == nil {
+			splits = []models.TransactionSplit{}
+		}
+	} else if len(existing.Splits) > 0 && amount != existing.Amount {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: "transaction has splits; send the new splits together with the new amount",
+		})
+		return
+	}
+
+	var tagIDs []uuid.UUID
+	if req.TagIDs != nil {
+		tagIDs = append([]uuid.UUID{}, req.TagIDs...)
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Update(id, userID, updates, splits, tagIDs); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to update transaction",
@@ -211,6 +396,15 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
+	updated := *existing
+	if req.Type != "" {
+		updated.Type = req.Type
+	}
+	if !req.Date.IsZero() {
+		updated.Date = req.Date
+	}
+	checkAlerts(h.alertEvaluator, userID, expenseDates([]models.Transaction{updated}))
+
 	c.JSON(http.StatusOK, models.Response{
 		Success: true,
 		Message: "Transaction updated successfully",
@@ -236,7 +430,7 @@ func (h *TransactionHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(id, userID); err != nil {
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).Delete(id, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to delete transaction",
@@ -250,3 +444,196 @@ func (h *TransactionHandler) Delete(c *gin.Context) {
 		Message: "Transaction deleted successfully",
 	})
 }
+
+// GetDuplicates lists the groups of suspected duplicates among the
+// transactions matching the list filters.
+func (h *TransactionHandler) GetDuplicates(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var filters models.TransactionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var transactions []models.Transaction
+	err = h.repo.Export(userID, filters, func(transaction *models.Transaction) error {
+		if transaction.TransferID == nil {
+			transactions = append(transactions, *transaction)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve transactions",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	groups := []models.DuplicateGroup{}
+	for _, group := range duplicates.Groups(transactions) {
+		groups = append(groups, models.DuplicateGroup{Transactions: group})
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    groups,
+	})
+}
+
+// MergeDuplicates keeps one transaction of a duplicate group and moves the
+// others to the trash, where they can be restored, after moving their tags
+// and any missing details to the kept one.
+func (h *TransactionHandler) MergeDuplicates(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req models.MergeDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	for _, id := range req.DuplicateIDs {
+		if id == req.KeepID {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid request data",
+				Message: "keep_id must not be one of duplicate_ids",
+			})
+			return
+		}
+	}
+
+	for _, id := range append([]uuid.UUID{req.KeepID}, req.DuplicateIDs...) {
+		transaction, err := h.repo.GetByID(id, userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error:   "Transaction not found",
+				Message: fmt.Sprintf("%s: %s", id, err.Error()),
+			})
+			return
+		}
+
+		if transaction.TransferID != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Transfers cannot be merged",
+				Message: id.String(),
+			})
+			return
+		}
+	}
+
+	if err := h.repo.WithAudit(middleware.GetAuditMeta(c)).MergeDuplicates(userID, req.KeepID, req.DuplicateIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to merge transactions",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	transaction, err := h.repo.GetByID(req.KeepID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to retrieve transaction",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Transactions merged successfully",
+		Data:    transaction,
+	})
+}
+
+// buildSplits converts the requested split lines, making sure they add up to
+// the transaction amount.
+func buildSplits(amount models.Money, reqs []models.SplitRequest) ([]models.TransactionSplit, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	var total models.Money
+	splits := make([]models.TransactionSplit, 0, len(reqs))
+	for _, req := range reqs {
+		total += req.Amount
+		splits = append(splits, models.TransactionSplit{
+			CategoryID: req.CategoryID,
+			Amount:     req.Amount,
+			Note:       req.Note,
+		})
+	}
+
+	if total != amount {
+		return nil, fmt.Errorf("split amounts must add up to the transaction amount")
+	}
+
+	return splits, nil
+}
+
+// ownsPayee reports whether payeeID, when set, is one of the user's payees,
+// writing the error response when it is not.
+func (h *TransactionHandler) ownsPayee(c *gin.Context, userID uuid.UUID, payeeID *uuid.UUID) bool {
+	if payeeID == nil {
+		return true
+	}
+
+	if _, err := h.payeeRepo.GetByID(*payeeID, userID); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Payee not found",
+			Message: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+// ownsAccount reports whether accountID, when set, is one of the user's
+// accounts, writing the error response when it is not.
+func ownsAccount(c *gin.Context, accountRepo *repository.AccountRepository, userID uuid.UUID, accountID *uuid.UUID) bool {
+	if accountID == nil {
+		return true
+	}
+
+	if _, err := accountRepo.GetByID(*accountID, userID); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Account not found",
+			Message: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/middleware"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TrashHandler struct {
+	transactionRepo *repository.TransactionRepository
+	categoryRepo    *repository.CategoryRepository
+	budgetRepo      *repository.BudgetRepository
+	goalRepo        *repository.GoalRepository
+	retention       time.Duration
+}
+
+func NewTrashHandler(
+	transactionRepo *repository.TransactionRepository,
+	categoryRepo *repository.CategoryRepository,
+	budgetRepo *repository.BudgetRepository,
+	goalRepo *repository.GoalRepository,
+	retention time.Duration,
+) *TrashHandler {
+	return &TrashHandler{
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		budgetRepo:      budgetRepo,
+		goalRepo:        goalRepo,
+		retention:       retention,
+	}
+}
+
+// List returns the records in the trash, optionally only one kind of them.
+func (h *TrashHandler) List(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var filters models.TrashFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	wants := func(kind string) bool {
+		return filters.Type == "" || filters.Type == kind
+	}
+
+	trash := models.Trash{RetentionDays: int(h.retention / (24 * time.Hour))}
+	err = func() (err error) {
+		if wants(models.TrashTransactions) {
+			if trash.Transactions, err = h.transactionRepo.GetDeleted(userID); err != nil {
+				return err
+			}
+		}
+		if wants(models.TrashCategories) {
+			if trash.Categories, err = h.categoryRepo.GetDeleted(userID); err != nil {
+				return err
+			}
+		}
+		if wants(models.TrashBudgets) {
+			if trash.Budgets, err = h.budgetRepo.GetDeleted(userID); err != nil {
+				return err
+			}
+		}
+		if wants(models.TrashGoals) {
+			trash.Goals, err = h.goalRepo.GetDeleted(userID)
+		}
+		return err
+	}()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to fetch trash",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if trash.Transactions == nil {
+		trash.Transactions = []models.Transaction{}
+	}
+	if trash.Categories == nil {
+		trash.Categories = []models.Category{}
+	}
+	if trash.Budgets == nil {
+		trash.Budgets = []models.Budget{}
+	}
+	if trash.Goals == nil {
+		trash.Goals = []models.FinancialGoal{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    trash,
+	})
+}
+
+// RestoreTransaction takes a transaction, and the other leg of a transfer,
+// out of the trash.
+func (h *TrashHandler) RestoreTransaction(c *gin.Context) {
+	restore(c, "transaction", h.transactionRepo.WithAudit(middleware.GetAuditMeta(c)).Restore)
+}
+
+func (h *TrashHandler) RestoreCategory(c *gin.Context) {
+	restore(c, "category", h.categoryRepo.WithAudit(middleware.GetAuditMeta(c)).Restore)
+}
+
+func (h *TrashHandler) RestoreBudget(c *gin.Context) {
+	restore(c, "budget", h.budgetRepo.WithAudit(middleware.GetAuditMeta(c)).Restore)
+}
+
+func (h *TrashHandler) RestoreGoal(c *gin.Context) {
+	restore(c, "goal", h.goalRepo.WithAudit(middleware.GetAuditMeta(c)).Restore)
+}
+
+// restore takes the record in the :id parameter out of the trash with fn;
+// entity names it in the responses.
+func restore(c *gin.Context, entity string, fn func(id, userID uuid.UUID) error) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	name := strings.ToUpper(entity[:1]) + entity[1:]
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid " + entity + " ID",
+		})
+		return
+	}
+
+	if err := fn(id, userID); err != nil {
+		if errors.Is(err, repository.ErrNotInTrash) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error:   name + " not found in trash",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to restore " + entity,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: name + " restored successfully",
+	})
+}
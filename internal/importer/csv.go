@@ -0,0 +1,305 @@
+// Package importer turns bank statement files into transaction rows that can
+// be previewed and then stored.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+const defaultDateFormat = "2006-01-02"
+
+// ParseCSV reads a statement using the column mapping of profile. Errors in a
+// single line are reported on its row; the returned error is only set when
+// the file as a whole cannot be read (bad header, unknown column...).
+func ParseCSV(r io.Reader, profile models.CSVImportProfile) ([]models.ImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if profile.Delimiter != "" {
+		reader.Comma = rune(profile.Delimiter[0])
+	}
+
+	var header []string
+	if profile.HasHeader == nil || *profile.HasHeader {
+		header, err = reader.Read()
+		if err == io.EOF {
+			return nil, fmt.Errorf("file is empty")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid header: %w", err)
+		}
+	}
+
+	mapping, err := newColumnMapping(header, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []models.ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, err
+			}
+			rows = append(rows, models.ImportRow{Line: line, Error: err.Error()})
+			continue
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		row, err := mapping.row(record)
+		row.Line = line
+		if err != nil {
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+type columnMapping struct {
+	date, amount, debit, credit, description int
+	dateLayout                               string
+	signConvention                           string
+	decimalSeparator                         string
+}
+
+func newColumnMapping(header []string, profile models.CSVImportProfile) (*columnMapping, error) {
+	m := &columnMapping{
+		amount:           -1,
+		debit:            -1,
+		credit:           -1,
+		description:      -1,
+		dateLayout:       DateLayout(profile.DateFormat),
+		signConvention:   profile.SignConvention,
+		decimalSeparator: profile.DecimalSeparator,
+	}
+	if m.signConvention == "" {
+		m.signConvention = "signed"
+	}
+	if m.decimalSeparator == "" {
+		m.decimalSeparator = "."
+	}
+
+	var err error
+	if m.date, err = columnIndex(header, profile.DateColumn); err != nil {
+		return nil, err
+	}
+
+	if m.signConvention == "debit_credit" {
+		if m.debit, err = columnIndex(header, profile.DebitColumn); err != nil {
+			return nil, err
+		}
+		if m.credit, err = columnIndex(header, profile.CreditColumn); err != nil {
+			return nil, err
+		}
+	} else if m.amount, err = columnIndex(header, profile.AmountColumn); err != nil {
+		return nil, err
+	}
+
+	if profile.DescriptionColumn != "" {
+		if m.description, err = columnIndex(header, profile.DescriptionColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// columnIndex resolves a column reference, either a 1-based position or a
+// header name (case-insensitive), to a 0-based index.
+func columnIndex(header []string, column string) (int, error) {
+	column = strings.TrimSpace(column)
+	if position, err := strconv.Atoi(column); err == nil {
+		if position < 1 {
+			return 0, fmt.Errorf("invalid column position %d", position)
+		}
+		return position - 1, nil
+	}
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+
+	if header == nil {
+		return 0, fmt.Errorf("column %q must be a position when the file has no header", column)
+	}
+	return 0, fmt.Errorf("column %q not found in header", column)
+}
+
+func (m *columnMapping) row(record []string) (models.ImportRow, error) {
+	var row models.ImportRow
+
+	if m.description >= 0 {
+		row.Description = field(record, m.description)
+	}
+
+	value := field(record, m.date)
+	date, err := time.Parse(m.dateLayout, value)
+	if err != nil {
+		return row, fmt.Errorf("invalid date %q (expected format %s)", value, m.dateLayout)
+	}
+	row.Date = date
+
+	var amount models.Money
+	switch m.signConvention {
+	case "debit_credit":
+		debit, err := m.optionalAmount(field(record, m.debit))
+		if err != nil {
+			return row, err
+		}
+		credit, err := m.optionalAmount(field(record, m.credit))
+		if err != nil {
+			return row, err
+		}
+		if debit != 0 && credit != 0 {
+			return row, fmt.Errorf("both debit and credit are filled")
+		}
+		// Debits are expenses whatever sign the bank writes them with.
+		if debit < 0 {
+			debit = -debit
+		}
+		amount = credit - debit
+	default:
+		amount, err = ParseAmount(field(record, m.amount), m.decimalSeparator)
+		if err != nil {
+			return row, err
+		}
+		if m.signConvention == "inverted" {
+			amount = -amount
+		}
+	}
+
+	if amount == 0 {
+		return row, fmt.Errorf("amount is zero")
+	}
+
+	row.Type = "income"
+	row.Amount = amount
+	if amount < 0 {
+		row.Type = "expense"
+		row.Amount = -amount
+	}
+
+	return row, nil
+}
+
+func (m *columnMapping) optionalAmount(value string) (models.Money, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return ParseAmount(value, m.decimalSeparator)
+}
+
+// ParseAmount parses a statement amount such as "-1.234,56", "R$ 10,00",
+// "(12.50)" or "15.00-" using the given decimal separator. Thousands
+// separators, currency symbols and spaces are ignored. Values with more than
+// one decimal separator or more than two decimals are rejected, since they
+// usually mean the wrong separator was chosen ("1.234,56" read with ".").
+func ParseAmount(value, decimalSeparator string) (models.Money, error) {
+	original := value
+	value = strings.TrimSpace(value)
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+	if strings.HasSuffix(value, "-") {
+		negative = true
+		value = strings.TrimSuffix(value, "-")
+	}
+
+	var b strings.Builder
+	separators, decimals := 0, 0
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			if separators > 0 {
+				decimals++
+			}
+		case r == '-':
+			negative = !negative
+		case string(r) == decimalSeparator:
+			b.WriteRune('.')
+			separators++
+		}
+	}
+
+	if separators > 1 || decimals > 2 {
+		return 0, fmt.Errorf("invalid amount %q", original)
+	}
+
+	amount, err := models.ParseMoney(b.String())
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", original)
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+// DateLayout converts patterns such as DD/MM/YYYY to a Go time layout.
+// Values that are already Go layouts are returned unchanged.
+func DateLayout(format string) string {
+	if format == "" {
+		return defaultDateFormat
+	}
+
+	upper := strings.ToUpper(format)
+	if !strings.Contains(upper, "YY") {
+		return format
+	}
+
+	replacer := strings.NewReplacer(
+		"YYYY", "2006",
+		"YY", "06",
+		"MM", "01",
+		"DD", "02",
+	)
+	return replacer.Replace(upper)
+}
+
+func field(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value     string
+		separator string
+		want      models.Money
+		wantErr   bool
+	}{
+		{value: "-1.234,56", separator: ",", want: -123456},
+		{value: "R$ 10,00", separator: ",", want: 1000},
+		{value: "(12.50)", separator: ".", want: -1250},
+		{value: "15.00-", separator: ".", want: -1500},
+		{value: "1,234.5", separator: ".", want: 123450},
+		{value: "42", separator: ",", want: 4200},
+		{value: "1.234,56", separator: ".", wantErr: true},
+		{value: "1,234.56", separator: ",", wantErr: true},
+		{value: "1.234.567", separator: ".", wantErr: true},
+		{value: "12.345", separator: ".", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.value, tt.separator)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAmount(%q, %q) = %s, want error", tt.value, tt.separator, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAmount(%q, %q): %v", tt.value, tt.separator, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q, %q) = %s, want %s", tt.value, tt.separator, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+// ofxElement is a node of an OFX document. In OFX 1.x (SGML) leaf elements
+// have no closing tag, so both versions are read with the same lenient
+// tokenizer instead of encoding/xml.
+type ofxElement struct {
+	name     string
+	text     string
+	children []*ofxElement
+}
+
+func (e *ofxElement) child(name string) *ofxElement {
+	for _, child := range e.children {
+		if child.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func (e *ofxElement) value(name string) string {
+	if child := e.child(name); child != nil {
+		return child.text
+	}
+	return ""
+}
+
+// walk calls fn for every element below e, depth first.
+func (e *ofxElement) walk(fn func(*ofxElement)) {
+	for _, child := range e.children {
+		fn(child)
+		child.walk(fn)
+	}
+}
+
+// ParseOFX reads the STMTTRN entries of an OFX/QFX file, version 1.x (SGML)
+// or 2.x (XML), from bank and credit card statements. Each row carries the
+// statement currency and an ExternalID made of the account number and FITID,
+// so re-importing the same file can be detected.
+func ParseOFX(r io.Reader) ([]models.ImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	content := decodeOFXText(data)
+	start := strings.Index(strings.ToUpper(content), "<OFX>")
+	if start < 0 {
+		return nil, fmt.Errorf("not an OFX file: <OFX> element not found")
+	}
+
+	root := parseOFXElements(content[start:])
+
+	var rows []models.ImportRow
+	line := 0
+	root.walk(func(statement *ofxElement) {
+		if statement.name != "STMTRS" && statement.name != "CCSTMTRS" {
+			return
+		}
+
+		currency := strings.ToUpper(statement.value("CURDEF"))
+		account := ""
+		for _, name := range []string{"BANKACCTFROM", "CCACCTFROM"} {
+			if from := statement.child(name); from != nil {
+				account = from.value("ACCTID")
+			}
+		}
+
+		statement.walk(func(entry *ofxElement) {
+			if entry.name != "STMTTRN" {
+				return
+			}
+			line++
+			row := ofxRow(entry, account, currency)
+			row.Line = line
+			rows = append(rows, row)
+		})
+	})
+
+	return rows, nil
+}
+
+func ofxRow(entry *ofxElement, account, currency string) models.ImportRow {
+	row := models.ImportRow{
+		Description: ofxDescription(entry),
+		Currency:    currency,
+	}
+	if original := entry.child("CURRENCY"); original != nil && original.value("CURSYM") != "" {
+		row.Currency = strings.ToUpper(original.value("CURSYM"))
+	}
+
+	fitID := entry.value("FITID")
+	if fitID == "" {
+		row.Error = "missing FITID"
+		return row
+	}
+	row.ExternalID = "ofx:" + fitID
+	if account != "" {
+		row.ExternalID = "ofx:" + account + ":" + fitID
+	}
+
+	date, err := parseOFXDate(entry.value("DTPOSTED"))
+	if err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	row.Date = date
+
+	value := entry.value("TRNAMT")
+	separator := "."
+	if strings.Contains(value, ",") && !strings.Contains(value, ".") {
+		separator = ","
+	}
+	amount, err := ParseAmount(value, separator)
+	if err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	if amount == 0 {
+		row.Error = "amount is zero"
+		return row
+	}
+
+	row.Type = "income"
+	row.Amount = amount
+	if amount < 0 {
+		row.Type = "expense"
+		row.Amount = -amount
+	}
+
+	return row
+}
+
+func ofxDescription(entry *ofxElement) string {
+	name := entry.value("NAME")
+	if name == "" {
+		if payee := entry.child("PAYEE"); payee != nil {
+			name = payee.value("NAME")
+		}
+	}
+	memo := entry.value("MEMO")
+
+	switch {
+	case name == "":
+		return memo
+	case memo == "" || strings.EqualFold(memo, name):
+		return name
+	default:
+		return name + " - " + memo
+	}
+}
+
+// parseOFXDate reads OFX datetimes such as 20240105, 20240105120000 or
+// 20240105120000.000[-3:BRT], keeping only the date.
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return date, nil
+}
+
+// ofxAggregates are the OFX elements that contain other elements. Any other
+// element without text is an empty leaf, unless the document closes it later.
+var ofxAggregates = map[string]bool{
+	"OFX":                true,
+	"SIGNONMSGSRSV1":     true,
+	"SONRS":              true,
+	"STATUS":             true,
+	"FI":                 true,
+	"BANKMSGSRSV1":       true,
+	"STMTTRNRS":          true,
+	"STMTRS":             true,
+	"BANKACCTFROM":       true,
+	"BANKACCTTO":         true,
+	"BANKTRANLIST":       true,
+	"STMTTRN":            true,
+	"PAYEE":              true,
+	"CURRENCY":           true,
+	"ORIGCURRENCY":       true,
+	"LEDGERBAL":          true,
+	"AVAILBAL":           true,
+	"BALLIST":            true,
+	"BAL":                true,
+	"CREDITCARDMSGSRSV1": true,
+	"CCSTMTTRNRS":        true,
+	"CCSTMTRS":           true,
+	"CCACCTFROM":         true,
+	"CCACCTTO":           true,
+}
+
+// parseOFXElements builds the element tree. An element followed by text is a
+// leaf; its closing tag, when present (OFX 2.x), is optional. An element
+// without text is an aggregate only when it is a known one or is closed later
+// in the document: SGML leaves such as an empty <MEMO> are never closed, and
+// self-closing (<NAME/>) or immediately closed (<NAME></NAME>) elements are
+// empty leaves. Closing tags pop the stack up to the matching element, which
+// also closes SGML aggregates whose leaves were never closed.
+func parseOFXElements(content string) *ofxElement {
+	root := &ofxElement{}
+	stack := []*ofxElement{root}
+	closed := ofxClosingTags(content)
+
+	for len(content) > 0 {
+		open := strings.IndexByte(content, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(content[open:], '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.TrimSpace(content[open+1 : open+end])
+		content = content[open+end+1:]
+
+		if tag == "" || tag[0] == '?' || tag[0] == '!' {
+			continue
+		}
+
+		if tag[0] == '/' {
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		selfClosing := strings.HasSuffix(tag, "/")
+		fields := strings.Fields(strings.TrimSuffix(tag, "/"))
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToUpper(fields[0])
+		element := &ofxElement{name: name}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, element)
+		if selfClosing {
+			continue
+		}
+
+		next := strings.IndexByte(content, '<')
+		if next < 0 {
+			next = len(content)
+		}
+		if text := strings.TrimSpace(content[:next]); text != "" {
+			element.text = unescapeOFX(text)
+			content = content[next:]
+			continue
+		}
+
+		if rest := content[next:]; strings.HasPrefix(strings.ToUpper(rest), "</"+name+">") {
+			content = rest[len(name)+3:]
+			continue
+		}
+
+		if ofxAggregates[name] || closed[name] {
+			stack = append(stack, element)
+		}
+	}
+
+	return root
+}
+
+// ofxClosingTags returns the names of the elements closed in the document.
+func ofxClosingTags(content string) map[string]bool {
+	names := make(map[string]bool)
+	for {
+		i := strings.Index(content, "</")
+		if i < 0 {
+			return names
+		}
+		content = content[i+2:]
+		end := strings.IndexByte(content, '>')
+		if end < 0 {
+			return names
+		}
+		names[strings.ToUpper(strings.TrimSpace(content[:end]))] = true
+		content = content[end+1:]
+	}
+}
+
+var ofxEntities = strings.NewReplacer(
+	"&lt;", "<",
+	"&gt;", ">",
+	"&quot;", `"`,
+	"&apos;", "'",
+	"&nbsp;", " ",
+	"&amp;", "&",
+)
+
+func unescapeOFX(text string) string {
+	return ofxEntities.Replace(text)
+}
+
+// decodeOFXText returns the file as UTF-8. OFX 1.x files are commonly
+// Latin-1/Windows-1252 (CHARSET:1252); bytes that are not valid UTF-8 are
+// read as Latin-1.
+func decodeOFXText(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+func TestParseOFXEmptyLeaves(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{
+			name: "SGML empty MEMO",
+			file: `OFXHEADER:100
+DATA:OFXSGML
+
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM>
+<BANKID>0341
+<ACCTID>12345
+</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<MEMO>
+<DTPOSTED>20240105120000[-3:BRT]
+<TRNAMT>-12.50
+<FITID>A1
+<NAME>PADARIA
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240106
+<TRNAMT>100.00
+<FITID>A2
+<MEMO>
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`,
+		},
+		{
+			name: "XML empty elements",
+			file: `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="211"?>
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<CURDEF>BRL</CURDEF>
+<BANKACCTFROM><BANKID>0341</BANKID><ACCTID>12345</ACCTID></BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT</TRNTYPE>
+<MEMO/>
+<DTPOSTED>20240105</DTPOSTED>
+<TRNAMT>-12.50</TRNAMT>
+<FITID>A1</FITID>
+<NAME>PADARIA</NAME>
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT</TRNTYPE>
+<DTPOSTED>20240106</DTPOSTED>
+<TRNAMT>100.00</TRNAMT>
+<FITID>A2</FITID>
+<MEMO></MEMO>
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`,
+		},
+	}
+
+	want := []models.ImportRow{
+		{Line: 1, Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Type: "expense", Amount: 1250, Description: "PADARIA", Currency: "BRL", ExternalID: "ofx:12345:A1"},
+		{Line: 2, Date: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), Type: "income", Amount: 10000, Description: "", Currency: "BRL", ExternalID: "ofx:12345:A2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseOFX(strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("ParseOFX: %v", err)
+			}
+			if len(rows) != len(want) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(want))
+			}
+			for i, row := range rows {
+				if row.Error != "" {
+					t.Fatalf("row %d: unexpected error %q", i+1, row.Error)
+				}
+				if row.Line != want[i].Line || !row.Date.Equal(want[i].Date) || row.Type != want[i].Type || row.Amount != want[i].Amount ||
+					row.Description != want[i].Description || row.Currency != want[i].Currency ||
+					row.ExternalID != want[i].ExternalID {
+					t.Errorf("row %d = %+v, want %+v", i+1, row, want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// MoneyFormat lets clients opt in to string-encoded amounts (e.g. "150.50"
+// instead of 150.50) by sending the header "X-Money-Format: string". JSON
+// responses are buffered and every field listed in models.MoneyFields is
+// re-encoded as a string; other responses pass through untouched.
+func MoneyFormat() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.EqualFold(c.GetHeader("X-Money-Format"), "string") {
+			c.Next()
+			return
+		}
+
+		writer := &moneyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if writer.buffer.Len() == 0 {
+			return
+		}
+
+		body := writer.buffer.Bytes()
+		var payload interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err == nil {
+			if encoded, err := json.Marshal(stringifyMoney(payload)); err == nil {
+				body = encoded
+			}
+		}
+
+		c.Writer.Write(body)
+	}
+}
+
+// moneyWriter buffers JSON bodies so they can be rewritten once the handler
+// is done. Non-JSON bodies (e.g. file exports) are written straight through.
+type moneyWriter struct {
+	gin.ResponseWriter
+	buffer bytes.Buffer
+}
+
+func (w *moneyWriter) isJSON() bool {
+	return strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+func (w *moneyWriter) Write(data []byte) (int, error) {
+	if !w.isJSON() {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buffer.Write(data)
+}
+
+func (w *moneyWriter) WriteString(s string) (int, error) {
+	if !w.isJSON() {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.buffer.WriteString(s)
+}
+
+func stringifyMoney(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if number, ok := field.(json.Number); ok && models.MoneyFields[key] {
+				v[key] = number.String()
+				continue
+			}
+			v[key] = stringifyMoney(field)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = stringifyMoney(v[i])
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMoneyFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(MoneyFormat())
+	router.GET("/json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"amount": models.Money(15050),
+			"count":  3,
+			"items": []gin.H{
+				{"balance": models.Money(-5), "name": "1.50"},
+			},
+		})
+	})
+	router.GET("/csv", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/csv", []byte("amount\n150.50\n"))
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   string
+	}{
+		{
+			name: "numbers by default",
+			path: "/json",
+			want: `{"amount":150.50,"count":3,"items":[{"balance":-0.05,"name":"1.50"}]}`,
+		},
+		{
+			name:   "strings when asked for",
+			path:   "/json",
+			header: "string",
+			want:   `{"amount":"150.50","count":3,"items":[{"balance":"-0.05","name":"1.50"}]}`,
+		},
+		{
+			name:   "header is case-insensitive",
+			path:   "/json",
+			header: "String",
+			want:   `{"amount":"150.50","count":3,"items":[{"balance":"-0.05","name":"1.50"}]}`,
+		},
+		{
+			name:   "non-JSON bodies untouched",
+			path:   "/csv",
+			header: "string",
+			want:   "amount\n150.50\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Money-Format", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
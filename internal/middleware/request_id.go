@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestID tags every request with an ID, echoed in the X-Request-ID
+// response header and recorded with the changes in the audit log. A client
+// sent ID is kept when it is short, printable ASCII.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+
+		c.Set("request_id", id)
+		c.Header(requestIDHeader, id)
+
+		c.Next()
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 100 {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+func GetRequestID(c *gin.Context) string {
+	return c.GetString("request_id")
+}
+
+// GetAuditMeta describes the current request for the audit log.
+func GetAuditMeta(c *gin.Context) models.AuditMeta {
+	userID, _ := GetUserID(c)
+
+	return models.AuditMeta{
+		ActorID:   userID,
+		RequestID: GetRequestID(c),
+		IP:        c.ClientIP(),
+	}
+}
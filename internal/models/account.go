@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Account struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	Name           string    `json:"name" db:"name" binding:"required,min=1,max=100"`
+	Type           string    `json:"type" db:"type" binding:"required,oneof=checking savings credit_card cash investment other"`
+	OpeningBalance Money     `json:"opening_balance" db:"opening_balance"`
+	Currency       string    `json:"currency" db:"currency" binding:"required,len=3,uppercase"`
+	Archived       bool      `json:"archived" db:"archived"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateAccountRequest struct {
+	Name           string `json:"name" binding:"required,min=1,max=100"`
+	Type           string `json:"type" binding:"required,oneof=checking savings credit_card cash investment other"`
+	OpeningBalance Money  `json:"opening_balance"`
+	Currency       string `json:"currency" binding:"omitempty,len=3,uppercase"`
+}
+
+type UpdateAccountRequest struct {
+	Name           string `json:"name" binding:"omitempty,min=1,max=100"`
+	Type           string `json:"type" binding:"omitempty,oneof=checking savings credit_card cash investment other"`
+	OpeningBalance *Money `json:"opening_balance"`
+	Currency       string `json:"currency" binding:"omitempty,len=3,uppercase"`
+	Archived       *bool  `json:"archived"`
+}
+
+// AccountBalance is the running balance of an account up to (and including) AsOf.
+type AccountBalance struct {
+	AccountID      uuid.UUID `json:"account_id" db:"account_id"`
+	Name           string    `json:"name" db:"name"`
+	Currency       string    `json:"currency" db:"currency"`
+	OpeningBalance Money     `json:"opening_balance" db:"opening_balance"`
+	Income         Money     `json:"income" db:"income"`
+	Expenses       Money     `json:"expenses" db:"expenses"`
+	Balance        Money     `json:"balance"`
+	AsOf           string    `json:"as_of"`
+	// MissingRates lists the currencies of transactions that could not be
+	// converted to the account currency and are left out of the balance.
+	MissingRates []string `json:"missing_rates,omitempty"`
+}
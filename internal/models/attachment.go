@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file (receipt photo, invoice PDF...) attached to a
+// transaction. The content itself is kept in the file storage.
+type Attachment struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	Filename      string    `json:"filename" db:"filename"`
+	ContentType   string    `json:"content_type" db:"content_type"`
+	Size          int64     `json:"size" db:"size"`
+	StorageKey    string    `json:"-" db:"storage_key"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
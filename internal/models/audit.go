@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of records recorded in the audit log.
+const (
+	AuditTransaction        = "transaction"
+	AuditCategory           = "category"
+	AuditBudget             = "budget"
+	AuditBudgetTemplate     = "budget_template"
+	AuditGoal               = "goal"
+	AuditAccount            = "account"
+	AuditTag                = "tag"
+	AuditPayee              = "payee"
+	AuditRecurringRule      = "recurring_rule"
+	AuditCategorizationRule = "categorization_rule"
+	AuditAttachment         = "attachment"
+	AuditSettings           = "settings"
+)
+
+// Actions recorded in the audit log. Delete and restore move a record in and
+// out of the trash; purge removes it for good.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// AuditMeta tells the audit log who made a change and from which request.
+type AuditMeta struct {
+	ActorID   uuid.UUID
+	RequestID string
+	IP        string
+}
+
+// AuditEntry is one change of a record. Before and After only hold the
+// fields that changed; ActorID is nil for changes made by background jobs.
+type AuditEntry struct {
+	ID         int64           `json:"id" db:"id"`
+	UserID     uuid.UUID       `json:"user_id" db:"user_id"`
+	ActorID    *uuid.UUID      `json:"actor_id" db:"actor_id"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id" db:"entity_id"`
+	Action     string          `json:"action" db:"action"`
+	Before     json.RawMessage `json:"before,omitempty" db:"before"`
+	After      json.RawMessage `json:"after,omitempty" db:"after"`
+	RequestID  *string         `json:"request_id" db:"request_id"`
+	IP         *string         `json:"ip" db:"ip"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilters narrows down the activity feed. After pages through it from
+// the next_cursor of the previous page, the feed being newest first.
+type AuditFilters struct {
+	EntityType string  `form:"entity_type" binding:"omitempty,oneof=transaction category budget budget_template goal account tag payee recurring_rule categorization_rule attachment settings"`
+	EntityID   *string `form:"entity_id" binding:"omitempty,uuid"`
+	Action     string  `form:"action" binding:"omitempty,oneof=create update delete restore purge"`
+	After      int64   `form:"after" binding:"omitempty,gte=1"`
+	Limit      int     `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
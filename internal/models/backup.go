@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// BackupData is everything a user owns, as stored in a backup archive.
+// Transactions carry their split lines and tags (by ID); categorization
+// rules reference categories and tags by ID too, and budget template items
+// categories.
+type BackupData struct {
+	Settings            *UserSettings        `json:"settings"`
+	Categories          []Category           `json:"categories"`
+	Accounts            []Account            `json:"accounts"`
+	Tags                []Tag                `json:"tags"`
+	Payees              []Payee              `json:"payees"`
+	RecurringRules      []RecurringRule      `json:"recurring_rules"`
+	CategorizationRules []CategorizationRule `json:"categorization_rules"`
+	Transactions        []Transaction        `json:"transactions"`
+	Budgets             []Budget             `json:"budgets"`
+	BudgetTemplates     []BudgetTemplate     `json:"budget_templates"`
+	Goals               []FinancialGoal      `json:"goals"`
+}
+
+// BackupManifest describes a backup archive: its format version and how many
+// records of each entity it holds.
+type BackupManifest struct {
+	Format    string         `json:"format"`
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Entities  map[string]int `json:"entities"`
+}
+
+type RestoreBackupRequest struct {
+	// Replace deletes the user's current data before restoring.
+	Replace bool `form:"replace"`
+}
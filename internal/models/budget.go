@@ -10,26 +10,103 @@ type Budget struct {
 	ID         uuid.UUID `json:"id" db:"id"`
 	UserID     uuid.UUID `json:"user_id" db:"user_id"`
 	CategoryID uuid.UUID `json:"category_id" db:"category_id" binding:"required"`
-	Amount     float64   `json:"amount" db:"amount" binding:"required,gt=0"`
+	Amount     Money     `json:"amount" db:"amount" binding:"required,gt=0"`
 	Month      time.Time `json:"month" db:"month" binding:"required"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	Category   *Category `json:"category,omitempty" db:"-"`
+	// Rollover carries what is left at the end of the month, or the
+	// overspend, into next month's budget of the same category.
+	Rollover bool `json:"rollover" db:"rollover"`
+	// AlertThresholds are the percentages of the available amount at which
+	// an alert is raised, e.g. [50, 80, 100].
+	AlertThresholds []int64   `json:"alert_thresholds" db:"alert_thresholds"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	Category        *Category `json:"category,omitempty" db:"-"`
+	// DeletedAt is only set on budgets listed in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type CreateBudgetRequest struct {
 	CategoryID uuid.UUID `json:"category_id" binding:"required"`
-	Amount     float64   `json:"amount" binding:"required,gt=0"`
+	Amount     Money     `json:"amount" binding:"required,gt=0"`
 	Month      time.Time `json:"month" binding:"required"`
+	Rollover   bool      `json:"rollover"`
+	// AlertThresholds are percentages between 1 and 1000.
+	AlertThresholds []int64 `json:"alert_thresholds" binding:"omitempty,max=10,dive,gte=1,lte=1000"`
 }
 
 type UpdateBudgetRequest struct {
-	Amount float64   `json:"amount" binding:"omitempty,gt=0"`
-	Month  time.Time `json:"month" binding:"omitempty"`
+	// Amount, Month and Rollover are left unchanged when absent.
+	Amount   *Money     `json:"amount" binding:"omitempty,gt=0"`
+	Month    *time.Time `json:"month"`
+	Rollover *bool      `json:"rollover"`
+	// AlertThresholds replaces the thresholds when present; an empty list
+	// removes them.
+	AlertThresholds []int64 `json:"alert_thresholds" binding:"omitempty,max=10,dive,gte=1,lte=1000"`
 }
 
+// Ways to handle a category that already has a budget in the month budgets
+// are copied or generated into.
+const (
+	BudgetConflictSkip      = "skip"
+	BudgetConflictOverwrite = "overwrite"
+)
+
+// Sources of generated budgets: a budget template, or the average spend per
+// category over the previous months.
+const (
+	BudgetSourceTemplate = "template"
+	BudgetSourceAverage  = "average"
+)
+
+// BudgetLine describes one budget to create in a month.
+type BudgetLine struct {
+	CategoryID uuid.UUID `json:"category_id" binding:"required"`
+	Amount     Money     `json:"amount" binding:"required,gt=0"`
+	Rollover   bool      `json:"rollover"`
+	// AlertThresholds are percentages between 1 and 1000.
+	AlertThresholds []int64 `json:"alert_thresholds,omitempty" binding:"omitempty,max=10,dive,gte=1,lte=1000"`
+}
+
+// CopyBudgetsRequest copies the budgets of one month to another. OnConflict
+// defaults to skip.
+type CopyBudgetsRequest struct {
+	FromMonth  time.Time `json:"from_month" binding:"required"`
+	ToMonth    time.Time `json:"to_month" binding:"required"`
+	OnConflict string    `json:"on_conflict" binding:"omitempty,oneof=skip overwrite"`
+}
+
+// GenerateBudgetsRequest creates the budgets of a month, next month by
+// default, from a template or from the average spend per expense category
+// over the Months before it (1 by default: last month's spend).
+type GenerateBudgetsRequest struct {
+	Month      *time.Time `json:"month"`
+	Source     string     `json:"source" binding:"required,oneof=template average"`
+	TemplateID *uuid.UUID `json:"template_id" binding:"required_if=Source template"`
+	Months     int        `json:"months" binding:"omitempty,gte=1,lte=12"`
+	OnConflict string     `json:"on_conflict" binding:"omitempty,oneof=skip overwrite"`
+}
+
+// BudgetApplyResult counts the budgets created in a month, the existing ones
+// overwritten and the lines skipped, because the category already had a
+// budget or no longer exists.
+type BudgetApplyResult struct {
+	Month   string `json:"month"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Skipped int    `json:"skipped"`
+}
+
+// BudgetWithSpent is a budget with what was spent in its category during
+// the month. Available is the amount plus CarriedOver, what the previous
+// month's budget left over (negative when overspent) if it has rollover;
+// Remaining and Percentage are relative to Available.
 type BudgetWithSpent struct {
 	Budget
-	Spent      float64 `json:"spent"`
-	Remaining  float64 `json:"remaining"`
-	Percentage float64 `json:"percentage"`
+	CarriedOver Money   `json:"carried_over"`
+	Available   Money   `json:"available"`
+	Spent       Money   `json:"spent"`
+	Remaining   Money   `json:"remaining"`
+	Percentage  float64 `json:"percentage"`
+	// MissingRates lists the currencies of expenses that could not be
+	// converted and are left out of Spent.
+	MissingRates []string `json:"missing_rates,omitempty"`
 }
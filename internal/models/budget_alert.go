@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BudgetAlert records that the spend of a budget crossed one of its alert
+// thresholds. Available, Spent and Percentage are the values at that moment.
+type BudgetAlert struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	BudgetID   uuid.UUID  `json:"budget_id" db:"budget_id"`
+	CategoryID uuid.UUID  `json:"category_id" db:"category_id"`
+	Month      time.Time  `json:"month" db:"month"`
+	Threshold  int64      `json:"threshold" db:"threshold"`
+	Available  Money      `json:"available" db:"available"`
+	Spent      Money      `json:"spent" db:"spent"`
+	Percentage float64    `json:"percentage" db:"percentage"`
+	ReadAt     *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	Category   *Category  `json:"category,omitempty" db:"-"`
+}
+
+type BudgetAlertFilters struct {
+	Month  *time.Time `form:"month" time_format:"2006-01-02"`
+	Unread bool       `form:"unread"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BudgetTemplate is a named set of budgets that can be applied to any month.
+type BudgetTemplate struct {
+	ID        uuid.UUID    `json:"id" db:"id"`
+	UserID    uuid.UUID    `json:"user_id" db:"user_id"`
+	Name      string       `json:"name" db:"name"`
+	Items     []BudgetLine `json:"items" db:"items"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+type CreateBudgetTemplateRequest struct {
+	Name  string       `json:"name" binding:"required,min=1,max=100"`
+	Items []BudgetLine `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+type UpdateBudgetTemplateRequest struct {
+	Name string `json:"name" binding:"omitempty,min=1,max=100"`
+	// Items replaces the items when present.
+	Items []BudgetLine `json:"items" binding:"omitempty,min=1,max=200,dive"`
+}
+
+// ApplyBudgetTemplateRequest creates the template's budgets in a month.
+// OnConflict defaults to skip.
+type ApplyBudgetTemplateRequest struct {
+	Month      time.Time `json:"month" binding:"required"`
+	OnConflict string    `json:"on_conflict" binding:"omitempty,oneof=skip overwrite"`
+}
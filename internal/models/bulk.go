@@ -0,0 +1,67 @@
+package models
+
+import "github.com/google/uuid"
+
+// MaxBulkItems is the largest number of transactions one bulk operation can
+// create, update or delete.
+const MaxBulkItems = 500
+
+// Statuses of the items of a bulk operation.
+const (
+	BulkStatusCreated = "created"
+	BulkStatusUpdated = "updated"
+	BulkStatusDeleted = "deleted"
+	BulkStatusFailed  = "failed"
+	// BulkStatusSkipped marks valid items that were not saved because another
+	// item of the batch failed.
+	BulkStatusSkipped = "skipped"
+)
+
+type BulkCreateTransactionsRequest struct {
+	Transactions []CreateTransactionRequest `json:"transactions" binding:"required,min=1,max=500"`
+}
+
+// BulkUpdateTransactionsRequest changes the transactions listed in IDs or,
+// when IDs is empty, the ones matching the list filters of the query string.
+// Fields left out are not changed.
+type BulkUpdateTransactionsRequest struct {
+	IDs        []uuid.UUID `json:"ids" binding:"omitempty,max=500"`
+	CategoryID *uuid.UUID  `json:"category_id"`
+	Type       string      `json:"type" binding:"omitempty,oneof=income expense"`
+	// TagIDs replaces the tags; AddTagIDs and RemoveTagIDs adjust them.
+	TagIDs       []uuid.UUID `json:"tag_ids"`
+	AddTagIDs    []uuid.UUID `json:"add_tag_ids"`
+	RemoveTagIDs []uuid.UUID `json:"remove_tag_ids"`
+	// DateShiftDays moves the dates forward (or back, when negative).
+	DateShiftDays int `json:"date_shift_days" binding:"omitempty,gte=-3660,lte=3660"`
+}
+
+// HasChanges reports whether the request changes anything.
+func (r BulkUpdateTransactionsRequest) HasChanges() bool {
+	return r.CategoryID != nil || r.Type != "" || r.TagIDs != nil ||
+		len(r.AddTagIDs) > 0 || len(r.RemoveTagIDs) > 0 || r.DateShiftDays != 0
+}
+
+// BulkDeleteTransactionsRequest deletes the transactions listed in IDs or,
+// when IDs is empty, the ones matching the list filters of the query string.
+type BulkDeleteTransactionsRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"omitempty,max=500"`
+}
+
+// BulkItemResult is the outcome of one item, by its position in the request
+// (or in the selection, for filters).
+type BulkItemResult struct {
+	Index  int        `json:"index"`
+	ID     *uuid.UUID `json:"id,omitempty"`
+	Status string     `json:"status"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// BulkResult reports every item of a bulk operation. Operations are atomic:
+// when Failed is not zero nothing was saved.
+type BulkResult struct {
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkItemResult `json:"items"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategorizationRule fills in fields of transactions that match its
+// conditions. Every condition set must hold: the description pattern
+// (case-insensitive substring or regular expression), the transaction type and
+// the amount range. Rules run by descending Priority; the first match wins.
+type CategorizationRule struct {
+	ID              uuid.UUID   `json:"id" db:"id"`
+	UserID          uuid.UUID   `json:"user_id" db:"user_id"`
+	Name            string      `json:"name" db:"name"`
+	Priority        int         `json:"priority" db:"priority"`
+	Active          bool        `json:"active" db:"active"`
+	MatchType       string      `json:"match_type" db:"match_type"`
+	Pattern         *string     `json:"pattern" db:"pattern"`
+	TransactionType *string     `json:"transaction_type" db:"transaction_type"`
+	MinAmount       *Money      `json:"min_amount" db:"min_amount"`
+	MaxAmount       *Money      `json:"max_amount" db:"max_amount"`
+	SetCategoryID   *uuid.UUID  `json:"set_category_id" db:"set_category_id"`
+	SetTagIDs       []uuid.UUID `json:"set_tag_ids" db:"set_tag_ids"`
+	SetDescription  *string     `json:"set_description" db:"set_description"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+type CreateCategorizationRuleRequest struct {
+	Name            string      `json:"name" binding:"required,min=1,max=100"`
+	Priority        int         `json:"priority"`
+	MatchType       string      `json:"match_type" binding:"omitempty,oneof=contains regex"`
+	Pattern         *string     `json:"pattern" binding:"omitempty,min=1,max=500"`
+	TransactionType *string     `json:"transaction_type" binding:"omitempty,oneof=income expense"`
+	MinAmount       *Money      `json:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount       *Money      `json:"max_amount" binding:"omitempty,gte=0"`
+	SetCategoryID   *uuid.UUID  `json:"set_category_id"`
+	SetTagIDs       []uuid.UUID `json:"set_tag_ids"`
+	SetDescription  *string     `json:"set_description" binding:"omitempty,min=1,max=500"`
+}
+
+type UpdateCategorizationRuleRequest struct {
+	Name            string      `json:"name" binding:"omitempty,min=1,max=100"`
+	Priority        *int        `json:"priority"`
+	Active          *bool       `json:"active"`
+	MatchType       string      `json:"match_type" binding:"omitempty,oneof=contains regex"`
+	Pattern         *string     `json:"pattern" binding:"omitempty,max=500"`
+	TransactionType *string     `json:"transaction_type" binding:"omitempty,oneof=income expense"`
+	MinAmount       *Money      `json:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount       *Money      `json:"max_amount" binding:"omitempty,gte=0"`
+	SetCategoryID   *uuid.UUID  `json:"set_category_id"`
+	SetTagIDs       []uuid.UUID `json:"set_tag_ids"`
+	SetDescription  *string     `json:"set_description" binding:"omitempty,max=500"`
+}
+
+type ApplyCategorizationRulesRequest struct {
+	// Overwrite replaces categories already set; by default only
+	// uncategorized transactions get one.
+	Overwrite bool `json:"overwrite"`
+	// DryRun reports the changes without saving them.
+	DryRun bool `json:"dry_run"`
+}
+
+// RuleChange is what applying the rules changes in one transaction.
+type RuleChange struct {
+	TransactionID uuid.UUID   `json:"transaction_id"`
+	RuleID        uuid.UUID   `json:"rule_id"`
+	CategoryID    *uuid.UUID  `json:"category_id,omitempty"`
+	Description   *string     `json:"description,omitempty"`
+	AddTagIDs     []uuid.UUID `json:"add_tag_ids,omitempty"`
+}
+
+type ApplyCategorizationRulesResult struct {
+	Matched int          `json:"matched"`
+	Updated int          `json:"updated"`
+	DryRun  bool         `json:"dry_run"`
+	Changes []RuleChange `json:"changes"`
+}
@@ -14,6 +14,8 @@ type Category struct {
 	Color     string    `json:"color" db:"color" binding:"required,hexcolor"`
 	Icon      string    `json:"icon" db:"icon" binding:"required"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// DeletedAt is only set on categories listed in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type CreateCategoryRequest struct {
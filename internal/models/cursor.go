@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Cursor marks a row of a sorted listing for keyset pagination: the order it
+// was taken from and the values of that row's sort keys, ending with its ID.
+// Clients receive it as an opaque token.
+type Cursor struct {
+	Order  string   `json:"o"`
+	Values []string `json:"v"`
+}
+
+// Encode returns the token form of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token returned by Encode.
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Order == "" || len(cursor.Values) == 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// UnmarshalParam lets gin bind cursors from query parameters.
+func (c *Cursor) UnmarshalParam(param string) error {
+	cursor, err := DecodeCursor(param)
+	if err != nil {
+		return err
+	}
+
+	*c = *cursor
+	return nil
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{
+		Order:  "-amount,date,id",
+		Values: []string{"150.50", "2024-01-05T00:00:00Z", "5b1f0c1e-8a4e-4c57-9d0b-2f1d6c3a7e90"},
+	}
+
+	got, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !reflect.DeepEqual(*got, cursor) {
+		t.Errorf("DecodeCursor(Encode()) = %+v, want %+v", *got, cursor)
+	}
+
+	var bound Cursor
+	if err := bound.UnmarshalParam(cursor.Encode()); err != nil {
+		t.Fatalf("UnmarshalParam: %v", err)
+	}
+	if !reflect.DeepEqual(bound, cursor) {
+		t.Errorf("UnmarshalParam = %+v, want %+v", bound, cursor)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "not a cursor!"},
+		{name: "not JSON", token: encode("date,id")},
+		{name: "no order", token: encode(`{"v":["1"]}`)},
+		{name: "no values", token: encode(`{"o":"id","v":[]}`)},
+		{name: "values not strings", token: encode(`{"o":"amount,id","v":[1,2]}`)},
+	}
+
+	for _, tt := range tests {
+		if got, err := DecodeCursor(tt.token); err == nil {
+			t.Errorf("%s: DecodeCursor(%q) = %+v, want error", tt.name, tt.token, got)
+		}
+	}
+}
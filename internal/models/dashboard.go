@@ -1,27 +1,32 @@
 package models
 
 type DashboardStats struct {
-	TotalIncome   float64 `json:"totalIncome"`
-	TotalExpenses float64 `json:"totalExpenses"`
-	Balance       float64 `json:"balance"`
+	TotalIncome   Money   `json:"totalIncome"`
+	TotalExpenses Money   `json:"totalExpenses"`
+	Balance       Money   `json:"balance"`
 	SavingsRate   float64 `json:"savingsRate"`
+	Currency      string  `json:"currency"`
+	// MissingRates lists the currencies of transactions in the period that
+	// have no exchange rate on their date; they are left out of the totals.
+	MissingRates []string         `json:"missingRates"`
+	Accounts     []AccountBalance `json:"accounts"`
 }
 
 type CategoryExpense struct {
 	Category   string  `json:"category" db:"category"`
-	Amount     float64 `json:"amount" db:"amount"`
+	Amount     Money   `json:"amount" db:"amount"`
 	Color      string  `json:"color" db:"color"`
 	Percentage float64 `json:"percentage"`
 }
 
 type MonthlyData struct {
-	Month    string  `json:"month" db:"month"`
-	Income   float64 `json:"income" db:"income"`
-	Expenses float64 `json:"expenses" db:"expenses"`
+	Month    string `json:"month" db:"month"`
+	Income   Money  `json:"income" db:"income"`
+	Expenses Money  `json:"expenses" db:"expenses"`
 }
 
 type DailyData struct {
-	Date     string  `json:"date" db:"date"`
-	Income   float64 `json:"income" db:"income"`
-	Expenses float64 `json:"expenses" db:"expenses"`
+	Date     string `json:"date" db:"date"`
+	Income   Money  `json:"income" db:"income"`
+	Expenses Money  `json:"expenses" db:"expenses"`
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ExchangeRate is a reference rate expressed as units of Currency per 1 EUR,
+// following the ECB convention.
+type ExchangeRate struct {
+	Currency string    `json:"currency" db:"currency"`
+	Date     time.Time `json:"date" db:"rate_date"`
+	Rate     float64   `json:"rate" db:"rate"`
+	Source   string    `json:"source" db:"source"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CSVImportProfile describes how the columns of a bank statement CSV map to
+// transaction fields. Columns are referenced by header name or, when the value
+// is a number, by 1-based position.
+type CSVImportProfile struct {
+	DateColumn string `form:"date_column" binding:"required"`
+	// DateFormat is a Go layout (02/01/2006) or a pattern such as DD/MM/YYYY.
+	DateFormat   string `form:"date_format"`
+	AmountColumn string `form:"amount_column" binding:"required_unless=SignConvention debit_credit"`
+	// SignConvention tells how the transaction type is derived from the amount:
+	// signed (negative = expense), inverted (positive = expense, as in credit
+	// card statements) or debit_credit (separate debit and credit columns).
+	SignConvention    string `form:"sign_convention" binding:"omitempty,oneof=signed inverted debit_credit"`
+	DebitColumn       string `form:"debit_column" binding:"required_if=SignConvention debit_credit"`
+	CreditColumn      string `form:"credit_column" binding:"required_if=SignConvention debit_credit"`
+	DecimalSeparator  string `form:"decimal_separator" binding:"omitempty,oneof=. 0x2C"`
+	DescriptionColumn string `form:"description_column"`
+	Delimiter         string `form:"delimiter" binding:"omitempty,len=1"`
+	// HasHeader defaults to true.
+	HasHeader *bool `form:"has_header"`
+	ImportOptions
+}
+
+// OFXImportRequest holds the form fields sent along with an OFX/QFX file.
+type OFXImportRequest struct {
+	ImportOptions
+}
+
+// ImportOptions are the settings shared by every statement import.
+type ImportOptions struct {
+	AccountID  *string `form:"account_id" binding:"omitempty,uuid"`
+	CategoryID *string `form:"category_id" binding:"omitempty,uuid"`
+	// Currency overrides the currency found in the file, if any, which in
+	// turn overrides the account currency.
+	Currency string `form:"currency" binding:"omitempty,len=3,uppercase"`
+	// DryRun only parses the file and returns the preview.
+	DryRun bool `form:"dry_run"`
+	// SkipInvalid imports the valid rows even when some rows have errors.
+	SkipInvalid bool `form:"skip_invalid"`
+	// AutoCategorize sets the category suggested from the user's history on
+	// rows still uncategorized after the rules, when the confidence is at
+	// least this value (0 to 1).
+	AutoCategorize *float64 `form:"auto_categorize" binding:"omitempty,gt=0,lte=1"`
+	// AllowDuplicates imports rows that look like existing transactions
+	// instead of skipping them as possible duplicates.
+	AllowDuplicates bool `form:"allow_duplicates"`
+}
+
+// Import row statuses.
+const (
+	ImportStatusNew               = "new"
+	ImportStatusDuplicate         = "duplicate"
+	ImportStatusPossibleDuplicate = "possible_duplicate"
+	ImportStatusInvalid           = "invalid"
+)
+
+// ImportRow is one parsed statement line. Error is set when the line could not
+// be turned into a transaction; Status tells whether it is new, already
+// imported (same ExternalID), similar to an existing transaction or invalid.
+type ImportRow struct {
+	Line        int       `json:"line"`
+	Date        time.Time `json:"date"`
+	Type        string    `json:"type"`
+	Amount      Money     `json:"amount"`
+	Description string    `json:"description"`
+	Currency    string    `json:"currency,omitempty"`
+	ExternalID  string    `json:"external_id,omitempty"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	// PayeeID is the payee matching the description, if any.
+	PayeeID *uuid.UUID `json:"payee_id,omitempty"`
+	// RuleID is the categorization rule applied to the row, if any.
+	RuleID *uuid.UUID `json:"rule_id,omitempty"`
+	// SuggestedCategoryID is the category set by auto_categorize, with the
+	// classifier confidence.
+	SuggestedCategoryID *uuid.UUID `json:"suggested_category_id,omitempty"`
+	Confidence          *float64   `json:"confidence,omitempty"`
+	// DuplicateOf lists the existing transactions a possible duplicate
+	// looks like.
+	DuplicateOf []uuid.UUID `json:"duplicate_of,omitempty"`
+}
+
+type ImportResult struct {
+	Total             int         `json:"total"`
+	Valid             int         `json:"valid"`
+	Duplicate         int         `json:"duplicate"`
+	PossibleDuplicate int         `json:"possible_duplicate"`
+	Invalid           int         `json:"invalid"`
+	Imported          int         `json:"imported"`
+	DryRun            bool        `json:"dry_run"`
+	Rows              []ImportRow `json:"rows"`
+}
@@ -0,0 +1,218 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of money stored as an integer number of minor units
+// (cents), so sums and comparisons are exact.
+//
+// Rounding policy: amounts are kept with two decimal places. Inputs (JSON,
+// query parameters and database values) with more precision are rounded to
+// the nearest cent, halves away from zero (10.005 -> 10.01, -10.005 -> -10.01).
+// Percentages derived from amounts are computed in float64 for display only
+// and never fed back into stored amounts.
+//
+// In JSON, Money is written as a number with exactly two decimals (e.g.
+// 150.50) and accepted either as a number or as a string ("150.50"). Clients
+// that want string amounts in responses can opt in with the MoneyFormat
+// middleware.
+type Money int64
+
+// ParseMoney parses a decimal string such as "150.5", "-3" or "1234.567"
+// without going through float64.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid money amount: empty string")
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid money amount: %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("invalid money amount: %q", s)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (1<<63-1)/100-1 {
+		return 0, fmt.Errorf("money amount out of range: %q", s)
+	}
+
+	fraction += "000"
+	cents, _ := strconv.ParseInt(fraction[:2], 10, 64)
+	value := units*100 + cents
+	if fraction[2] >= '5' {
+		value++
+	}
+
+	if negative {
+		value = -value
+	}
+
+	return Money(value), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MoneyFromCents builds a Money value from an integer number of cents.
+func MoneyFromCents(cents int64) Money {
+	return Money(cents)
+}
+
+// Cents returns the amount in minor units.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns an approximate float64 value, meant for ratios and display.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// Percent returns m as a percentage of total, or 0 when total is not positive.
+func (m Money) Percent(total Money) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(m) / float64(total) * 100
+}
+
+// String formats the amount with exactly two decimals, e.g. "-1234.05".
+func (m Money) String() string {
+	value := int64(m)
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	// Accept exponent notation (e.g. 1e3) by expanding it first.
+	if strings.ContainsAny(text, "eE") {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("invalid money amount: %s", text)
+		}
+		text = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// UnmarshalParam lets gin bind Money from query and form parameters.
+func (m *Money) UnmarshalParam(param string) error {
+	parsed, err := ParseMoney(param)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Scan reads NUMERIC columns, which the driver returns as text, exactly.
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		parsed, err := ParseMoney(string(value))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := ParseMoney(value)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case int64:
+		*m = Money(value * 100)
+		return nil
+	case float64:
+		parsed, err := ParseMoney(strconv.FormatFloat(value, 'f', -1, 64))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+// Value writes the amount as a decimal string so NUMERIC columns receive the
+// exact value.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// MoneyFields lists the JSON keys of response fields holding Money values.
+// The MoneyFormat middleware re-encodes them as strings for clients that opt in.
+var MoneyFields = map[string]bool{
+	"amount":          true,
+	"opening_balance": true,
+	"balance":         true,
+	"income":          true,
+	"expenses":        true,
+	"totalIncome":     true,
+	"totalExpenses":   true,
+	"spent":           true,
+	"remaining":       true,
+	"carried_over":    true,
+	"available":       true,
+	"target_amount":   true,
+	"current_amount":  true,
+	"min_amount":      true,
+	"max_amount":      true,
+	"average":         true,
+}
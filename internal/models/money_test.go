@@ -0,0 +1,123 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Money
+		wantErr bool
+	}{
+		{value: "150.5", want: 15050},
+		{value: "-3", want: -300},
+		{value: "+7.25", want: 725},
+		{value: ".5", want: 50},
+		{value: "12.", want: 1200},
+		{value: " 42.10 ", want: 4210},
+		{value: "1234.567", want: 123457},
+		{value: "10.005", want: 1001},
+		{value: "10.0049", want: 1000},
+		{value: "9.995", want: 1000},
+		{value: "-10.005", want: -1001},
+		{value: "-0.005", want: -1},
+		{value: "", wantErr: true},
+		{value: "-", wantErr: true},
+		{value: ".", wantErr: true},
+		{value: "1,50", wantErr: true},
+		{value: "1.2.3", wantErr: true},
+		{value: "--1", wantErr: true},
+		{value: "abc", wantErr: true},
+		{value: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %s, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q): %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		value Money
+		want  string
+	}{
+		{value: 0, want: "0.00"},
+		{value: 5, want: "0.05"},
+		{value: -5, want: "-0.05"},
+		{value: 15050, want: "150.50"},
+		{value: -123405, want: "-1234.05"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(tt.value), got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{data: `150.5`, want: 15050},
+		{data: `"150.50"`, want: 15050},
+		{data: `-12`, want: -1200},
+		{data: `"-12"`, want: -1200},
+		{data: `10.005`, want: 1001},
+		{data: `"10.005"`, want: 1001},
+		{data: `1e3`, want: 100000},
+		{data: `1.5E2`, want: 15000},
+		{data: `null`, want: 0},
+		{data: `""`, wantErr: true},
+		{data: `"abc"`, wantErr: true},
+		{data: `true`, wantErr: true},
+		{data: `"1e"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %s, want error", tt.data, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.data, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{Amount: -123405})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"amount":-1234.05}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payee is a merchant or other counterparty. Transactions whose description
+// contains the name or one of the aliases are linked to it.
+type Payee struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Aliases   []string  `json:"aliases" db:"aliases"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreatePayeeRequest struct {
+	Name    string   `json:"name" binding:"required,min=1,max=100"`
+	Aliases []string `json:"aliases" binding:"omitempty,max=50,dive,min=1,max=100"`
+}
+
+type UpdatePayeeRequest struct {
+	Name string `json:"name" binding:"omitempty,min=1,max=100"`
+	// Aliases replaces the aliases when present; an empty list removes them.
+	Aliases []string `json:"aliases" binding:"omitempty,max=50,dive,min=1,max=100"`
+}
+
+// ApplyPayeesRequest links existing transactions to payees.
+type ApplyPayeesRequest struct {
+	// Overwrite also relinks transactions that already have a payee.
+	Overwrite bool `json:"overwrite"`
+	DryRun    bool `json:"dry_run"`
+}
+
+// ApplyPayeesResult counts the transactions whose description matches a payee
+// and, among them, the ones (to be) linked to a new payee.
+type ApplyPayeesResult struct {
+	Matched int  `json:"matched"`
+	Linked  int  `json:"linked"`
+	DryRun  bool `json:"dry_run"`
+}
+
+// PayeeSummary aggregates the transactions of a payee.
+type PayeeSummary struct {
+	PayeeID uuid.UUID `json:"payee_id" db:"payee_id"`
+	Payee   string    `json:"payee" db:"payee"`
+	Amount  Money     `json:"amount" db:"amount"`
+	Count   int64     `json:"count" db:"count"`
+	Average Money     `json:"average" db:"average"`
+	// MissingRates lists the currencies of transactions that could not be
+	// converted and are left out of Amount and Average.
+	MissingRates []string `json:"missing_rates,omitempty" db:"missing_rates"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurringRule describes a transaction that repeats every Interval units of
+// Frequency starting at StartDate. Monthly and yearly rules fall on DayOfMonth
+// (defaulting to the day of StartDate), clamped to the last day of short months.
+type RecurringRule struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	Frequency   string     `json:"frequency" db:"frequency"`
+	Interval    int        `json:"interval" db:"interval"`
+	StartDate   time.Time  `json:"start_date" db:"start_date"`
+	EndDate     *time.Time `json:"end_date" db:"end_date"`
+	DayOfMonth  *int       `json:"day_of_month" db:"day_of_month"`
+	CategoryID  *uuid.UUID `json:"category_id" db:"category_id"`
+	AccountID   *uuid.UUID `json:"account_id" db:"account_id"`
+	Type        string     `json:"type" db:"type"`
+	Amount      Money      `json:"amount" db:"amount"`
+	Description *string    `json:"description" db:"description"`
+	Active      bool       `json:"active" db:"active"`
+	LastRunDate *time.Time `json:"last_run_date" db:"last_run_date"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type CreateRecurringRuleRequest struct {
+	Frequency   string     `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	Interval    int        `json:"interval" binding:"omitempty,gte=1,lte=365"`
+	StartDate   time.Time  `json:"start_date" binding:"required"`
+	EndDate     *time.Time `json:"end_date"`
+	DayOfMonth  *int       `json:"day_of_month" binding:"omitempty,gte=1,lte=31"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	AccountID   *uuid.UUID `json:"account_id"`
+	Type        string     `json:"type" binding:"required,oneof=income expense"`
+	Amount      Money      `json:"amount" binding:"required,gt=0"`
+	Description *string    `json:"description"`
+}
+
+type UpdateRecurringRuleRequest struct {
+	Frequency   string     `json:"frequency" binding:"omitempty,oneof=daily weekly monthly yearly"`
+	Interval    int        `json:"interval" binding:"omitempty,gte=1,lte=365"`
+	EndDate     *time.Time `json:"end_date"`
+	DayOfMonth  *int       `json:"day_of_month" binding:"omitempty,gte=1,lte=31"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	AccountID   *uuid.UUID `json:"account_id"`
+	Type        string     `json:"type" binding:"omitempty,oneof=income expense"`
+	Amount      Money      `json:"amount" binding:"omitempty,gt=0"`
+	Description *string    `json:"description"`
+	Active      *bool      `json:"active"`
+}
+
+// RecurringOccurrence is a transaction a rule will create on Date.
+type RecurringOccurrence struct {
+	Date        string     `json:"date"`
+	Type        string     `json:"type"`
+	Amount      Money      `json:"amount"`
+	Description *string    `json:"description"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	AccountID   *uuid.UUID `json:"account_id"`
+}
@@ -13,13 +13,18 @@ type ErrorResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// PaginatedResponse is a page of results. Page is only set when paging by
+// number and the totals are left out when the count was skipped; the cursors,
+// when present, fetch the following and preceding pages.
 type PaginatedResponse struct {
 	Success    bool        `json:"success"`
 	Data       interface{} `json:"data"`
-	Page       int         `json:"page"`
+	Page       int         `json:"page,omitempty"`
 	Limit      int         `json:"limit"`
-	TotalCount int64       `json:"total_count"`
-	TotalPages int         `json:"total_pages"`
+	TotalCount *int64      `json:"total_count,omitempty"`
+	TotalPages *int        `json:"total_pages,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
 }
 
 type HealthCheck struct {
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSortKeys limits how many fields a sort specification can combine.
+const maxSortKeys = 5
+
+// SortKey is one field of a sort specification.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// TransactionSortFields are the fields transactions can be sorted by.
+var TransactionSortFields = []string{"date", "amount", "category", "created_at", "updated_at"}
+
+// TransactionSort is a comma-separated list of TransactionSortFields, each
+// prefixed with "-" for descending order: "-amount,date" sorts by amount,
+// largest first, then by date. Only whitelisted fields are accepted.
+type TransactionSort string
+
+// ParseTransactionSort splits a sort specification into its keys.
+func ParseTransactionSort(sort string) ([]SortKey, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		key := SortKey{Field: strings.TrimSpace(part)}
+		if strings.HasPrefix(key.Field, "-") {
+			key.Field = key.Field[1:]
+			key.Desc = true
+		}
+
+		if !isTransactionSortField(key.Field) {
+			return nil, fmt.Errorf("invalid sort field %q (use %s)", key.Field, strings.Join(TransactionSortFields, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("sort field %q repeated", key.Field)
+		}
+		seen[key.Field] = true
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) > maxSortKeys {
+		return nil, fmt.Errorf("at most %d sort fields are allowed", maxSortKeys)
+	}
+
+	return keys, nil
+}
+
+func isTransactionSortField(field string) bool {
+	for _, allowed := range TransactionSortFields {
+		if field == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalParam lets gin bind and validate the sort query parameter.
+func (s *TransactionSort) UnmarshalParam(param string) error {
+	if _, err := ParseTransactionSort(param); err != nil {
+		return err
+	}
+
+	*s = TransactionSort(param)
+	return nil
+}
+
+// Keys returns the keys of a sort specification bound by UnmarshalParam, or
+// nil when it is empty or invalid.
+func (s TransactionSort) Keys() []SortKey {
+	keys, err := ParseTransactionSort(string(s))
+	if err != nil {
+		return nil
+	}
+	return keys
+}
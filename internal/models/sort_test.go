@@ -0,0 +1,62 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTransactionSort(t *testing.T) {
+	tests := []struct {
+		sort    string
+		want    []SortKey
+		wantErr bool
+	}{
+		{sort: "", want: nil},
+		{sort: "  ", want: nil},
+		{sort: "date", want: []SortKey{{Field: "date"}}},
+		{sort: "-amount,date", want: []SortKey{{Field: "amount", Desc: true}, {Field: "date"}}},
+		{sort: " category , -updated_at", want: []SortKey{{Field: "category"}, {Field: "updated_at", Desc: true}}},
+		{sort: "date,amount,category,created_at,updated_at", want: []SortKey{
+			{Field: "date"}, {Field: "amount"}, {Field: "category"}, {Field: "created_at"}, {Field: "updated_at"},
+		}},
+		{sort: "id", wantErr: true},
+		{sort: "rank", wantErr: true},
+		{sort: "description", wantErr: true},
+		{sort: "t.amount; DROP TABLE transactions", wantErr: true},
+		{sort: "+date", wantErr: true},
+		{sort: "--date", wantErr: true},
+		{sort: "Date", wantErr: true},
+		{sort: "date,", wantErr: true},
+		{sort: "date,-date", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTransactionSort(tt.sort)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTransactionSort(%q) = %v, want error", tt.sort, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTransactionSort(%q): %v", tt.sort, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTransactionSort(%q) = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestTransactionSortUnmarshalParam(t *testing.T) {
+	var sort TransactionSort
+	if err := sort.UnmarshalParam("-amount"); err != nil {
+		t.Fatalf("UnmarshalParam: %v", err)
+	}
+	if err := sort.UnmarshalParam("password"); err == nil {
+		t.Errorf("UnmarshalParam(%q) succeeded, want error", "password")
+	}
+	if want := []SortKey{{Field: "amount", Desc: true}}; !reflect.DeepEqual(sort.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", sort.Keys(), want)
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name" binding:"required,min=1,max=50"`
+	Color     string    `json:"color" db:"color" binding:"required,hexcolor"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,min=1,max=50"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type UpdateTagRequest struct {
+	Name  string `json:"name" binding:"omitempty,min=1,max=50"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+// TagSummary aggregates the income and expenses of the transactions carrying a tag.
+type TagSummary struct {
+	TagID    uuid.UUID `json:"tag_id" db:"tag_id"`
+	Tag      string    `json:"tag" db:"tag"`
+	Color    string    `json:"color" db:"color"`
+	Income   Money     `json:"income" db:"income"`
+	Expenses Money     `json:"expenses" db:"expenses"`
+	Count    int64     `json:"count" db:"count"`
+}
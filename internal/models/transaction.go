@@ -7,41 +7,153 @@ import (
 )
 
 type Transaction struct {
-	ID          uuid.UUID  `json:"id" db:"id"`
-	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
-	CategoryID  *uuid.UUID `json:"category_id" db:"category_id"`
-	Type        string     `json:"type" db:"type" binding:"required,oneof=income expense"`
-	Amount      float64    `json:"amount" db:"amount" binding:"required,gt=0"`
-	Description *string    `json:"description" db:"description"`
-	Date        time.Time  `json:"date" db:"date" binding:"required"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-	Category    *Category  `json:"category,omitempty" db:"-"`
+	ID              uuid.UUID          `json:"id" db:"id"`
+	UserID          uuid.UUID          `json:"user_id" db:"user_id"`
+	CategoryID      *uuid.UUID         `json:"category_id" db:"category_id"`
+	AccountID       *uuid.UUID         `json:"account_id" db:"account_id"`
+	PayeeID         *uuid.UUID         `json:"payee_id" db:"payee_id"`
+	TransferID      *uuid.UUID         `json:"transfer_id" db:"transfer_id"`
+	RecurringRuleID *uuid.UUID         `json:"recurring_rule_id" db:"recurring_rule_id"`
+	Type            string             `json:"type" db:"type" binding:"required,oneof=income expense"`
+	Amount          Money              `json:"amount" db:"amount" binding:"required,gt=0"`
+	Currency        string             `json:"currency" db:"currency"`
+	Description     *string            `json:"description" db:"description"`
+	ExternalID      *string            `json:"external_id" db:"external_id"`
+	Date            time.Time          `json:"date" db:"date" binding:"required"`
+	CreatedAt       time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" db:"updated_at"`
+	Category        *Category          `json:"category,omitempty" db:"-"`
+	Splits          []TransactionSplit `json:"splits,omitempty" db:"-"`
+	Tags            []Tag              `json:"tags,omitempty" db:"-"`
+	// Rank and Highlight are only set when searching with q: the relevance
+	// and an HTML snippet of the matched text, terms wrapped in <mark>.
+	Rank      *float64 `json:"rank,omitempty" db:"-"`
+	Highlight *string  `json:"highlight,omitempty" db:"-"`
+	// DeletedAt is only set on transactions listed in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// TransactionSplit is one line of a transaction spread over several
+// categories. The amounts of all lines add up to the transaction amount.
+type TransactionSplit struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TransactionID uuid.UUID  `json:"transaction_id" db:"transaction_id"`
+	CategoryID    *uuid.UUID `json:"category_id" db:"category_id"`
+	Amount        Money      `json:"amount" db:"amount"`
+	Note          *string    `json:"note" db:"note"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	Category      *Category  `json:"category,omitempty" db:"-"`
+}
+
+type SplitRequest struct {
+	CategoryID *uuid.UUID `json:"category_id"`
+	Amount     Money      `json:"amount" binding:"required,gt=0"`
+	Note       *string    `json:"note"`
 }
 
 type CreateTransactionRequest struct {
-	CategoryID  *uuid.UUID `json:"category_id"`
-	Type        string     `json:"type" binding:"required,oneof=income expense"`
-	Amount      float64    `json:"amount" binding:"required,gt=0"`
-	Description *string    `json:"description"`
-	Date        time.Time  `json:"date" binding:"required"`
+	CategoryID *uuid.UUID `json:"category_id"`
+	AccountID  *uuid.UUID `json:"account_id"`
+	// PayeeID defaults to the payee matching the description, if any.
+	PayeeID *uuid.UUID `json:"payee_id"`
+	Type    string     `json:"type" binding:"required,oneof=income expense"`
+	Amount  Money      `json:"amount" binding:"required,gt=0"`
+	// Currency defaults to the account currency, then the user base currency.
+	Currency    string         `json:"currency" binding:"omitempty,len=3,uppercase"`
+	Description *string        `json:"description"`
+	Date        time.Time      `json:"date" binding:"required"`
+	Splits      []SplitRequest `json:"splits" binding:"omitempty,dive"`
+	TagIDs      []uuid.UUID    `json:"tag_ids"`
 }
 
 type UpdateTransactionRequest struct {
 	CategoryID  *uuid.UUID `json:"category_id"`
+	AccountID   *uuid.UUID `json:"account_id"`
+	PayeeID     *uuid.UUID `json:"payee_id"`
 	Type        string     `json:"type" binding:"omitempty,oneof=income expense"`
-	Amount      float64    `json:"amount" binding:"omitempty,gt=0"`
+	Amount      Money      `json:"amount" binding:"omitempty,gt=0"`
+	Currency    string     `json:"currency" binding:"omitempty,len=3,uppercase"`
 	Description *string    `json:"description"`
 	Date        time.Time  `json:"date"`
+	// Splits replaces the split lines when present; an empty list removes them.
+	Splits []SplitRequest `json:"splits" binding:"omitempty,dive"`
+	// TagIDs replaces the tags when present; an empty list removes them.
+	TagIDs []uuid.UUID `json:"tag_ids"`
+}
+
+// SuggestCategoryRequest describes the transaction to suggest a category for.
+// Only the description is required; type and amount refine the suggestion.
+type SuggestCategoryRequest struct {
+	Description string `form:"description" binding:"required"`
+	Type        string `form:"type" binding:"omitempty,oneof=income expense"`
+	Amount      Money  `form:"amount" binding:"omitempty,gte=0"`
+	Limit       int    `form:"limit" binding:"omitempty,gte=1,lte=10"`
+}
+
+// CategorySuggestion is a candidate category with the classifier confidence,
+// between 0 and 1.
+type CategorySuggestion struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Category   *Category `json:"category,omitempty"`
+	Confidence float64   `json:"confidence"`
+}
+
+// DuplicateGroup is a set of transactions that look like the same one.
+type DuplicateGroup struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+// MergeDuplicatesRequest keeps one transaction of a duplicate group and moves
+// the others to the trash, where they can be restored, after moving their
+// tags and missing details to it.
+type MergeDuplicatesRequest struct {
+	KeepID       uuid.UUID   `json:"keep_id" binding:"required"`
+	DuplicateIDs []uuid.UUID `json:"duplicate_ids" binding:"required,min=1,max=50"`
 }
 
 type TransactionFilters struct {
-	Type       string     `form:"type" binding:"omitempty,oneof=income expense"`
-	CategoryID *uuid.UUID `form:"category_id"`
+	Type string `form:"type" binding:"omitempty,oneof=income expense"`
+	// IDs are bound as strings: gin's form binding cannot decode uuid.UUID.
+	CategoryID *string    `form:"category_id" binding:"omitempty,uuid"`
+	AccountID  *string    `form:"account_id" binding:"omitempty,uuid"`
+	PayeeID    *string    `form:"payee_id" binding:"omitempty,uuid"`
 	StartDate  *time.Time `form:"start_date"`
 	EndDate    *time.Time `form:"end_date"`
-	MinAmount  *float64   `form:"min_amount" binding:"omitempty,gte=0"`
-	MaxAmount  *float64   `form:"max_amount" binding:"omitempty,gte=0"`
-	Page       int        `form:"page" binding:"omitempty,gte=1"`
-	Limit      int        `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	MinAmount  *Money     `form:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount  *Money     `form:"max_amount" binding:"omitempty,gte=0"`
+	// Q searches the description, payee, categories and split notes, ignoring
+	// accents and word endings. Quoted phrases, "or" and -word are supported.
+	Q string `form:"q" binding:"omitempty,max=200"`
+	// Sort orders the results, e.g. sort=-amount,date (see TransactionSort).
+	Sort TransactionSort `form:"sort"`
+	// Tags filters by tag IDs (repeat the parameter for several tags). With
+	// tag_match=any (default) a transaction needs one of them, with all every one.
+	Tags     []string `form:"tags" binding:"omitempty,dive,uuid"`
+	TagMatch string   `form:"tag_match" binding:"omitempty,oneof=any all"`
+	Page     int      `form:"page" binding:"omitempty,gte=1"`
+	Limit    int      `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	// After and Before page through the results from a cursor returned in
+	// next_cursor or prev_cursor, instead of page. SkipCount leaves out the
+	// total count, which is slow on long histories.
+	After     *Cursor `form:"after" binding:"excluded_with=Before"`
+	Before    *Cursor `form:"before"`
+	SkipCount bool    `form:"skip_count"`
+}
+
+// HasFilter reports whether the filters narrow the transactions down; paging
+// and sorting parameters do not.
+func (f TransactionFilters) HasFilter() bool {
+	return f.Type != "" || f.CategoryID != nil || f.AccountID != nil || f.PayeeID != nil ||
+		f.StartDate != nil || f.EndDate != nil || f.MinAmount != nil || f.MaxAmount != nil ||
+		f.Q != "" || len(f.Tags) > 0
+}
+
+// TransactionPage is one page of a transaction listing. TotalCount is nil
+// when the count was skipped; the cursors are empty when there are no more
+// rows in their direction.
+type TransactionPage struct {
+	Transactions []Transaction
+	TotalCount   *int64
+	NextCursor   string
+	PrevCursor   string
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transfer moves money between two accounts of the same user. It is persisted
+// as two linked transactions: an expense leg on the source account and an
+// income leg on the destination account. Transfer legs are not counted as
+// income or expenses in dashboard and budget aggregates.
+type Transfer struct {
+	ID            uuid.UUID    `json:"id"`
+	FromAccountID uuid.UUID    `json:"from_account_id"`
+	ToAccountID   uuid.UUID    `json:"to_account_id"`
+	Amount        Money        `json:"amount"`
+	Description   *string      `json:"description"`
+	Date          time.Time    `json:"date"`
+	Outgoing      *Transaction `json:"outgoing"`
+	Incoming      *Transaction `json:"incoming"`
+}
+
+type CreateTransferRequest struct {
+	FromAccountID uuid.UUID `json:"from_account_id" binding:"required"`
+	ToAccountID   uuid.UUID `json:"to_account_id" binding:"required,nefield=FromAccountID"`
+	Amount        Money     `json:"amount" binding:"required,gt=0"`
+	// ToAmount is the amount credited to the destination account when the
+	// accounts use different currencies. Defaults to Amount.
+	ToAmount    *Money    `json:"to_amount" binding:"omitempty,gt=0"`
+	Description *string   `json:"description"`
+	Date        time.Time `json:"date" binding:"required"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Kinds of records that can be moved to the trash.
+const (
+	TrashTransactions = "transactions"
+	TrashCategories   = "categories"
+	TrashBudgets      = "budgets"
+	TrashGoals        = "goals"
+)
+
+// TrashFilters narrows the trash listing down to one kind of record.
+type TrashFilters struct {
+	Type string `form:"type" binding:"omitempty,oneof=transactions categories budgets goals"`
+}
+
+// Trash lists the deleted records that can still be restored, most recently
+// deleted first. They are purged RetentionDays after being deleted.
+type Trash struct {
+	Transactions  []Transaction   `json:"transactions"`
+	Categories    []Category      `json:"categories"`
+	Budgets       []Budget        `json:"budgets"`
+	Goals         []FinancialGoal `json:"goals"`
+	RetentionDays int             `json:"retention_days"`
+}
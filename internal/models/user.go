@@ -13,6 +13,23 @@ type User struct {
 	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// DefaultCurrency is used when neither the account nor the user settings
+// define a currency.
+const DefaultCurrency = "BRL"
+
+// UserSettings holds per-user preferences. BaseCurrency is the currency all
+// dashboard and budget aggregates are converted to.
+type UserSettings struct {
+	UserID       uuid.UUID `json:"user_id" db:"user_id"`
+	BaseCurrency string    `json:"base_currency" db:"base_currency"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type UpdateUserSettingsRequest struct {
+	BaseCurrency string `json:"base_currency" binding:"required,len=3,uppercase"`
+}
+
 type AuthUser struct {
 	ID    uuid.UUID `json:"sub"`
 	Email string    `json:"email"`
@@ -0,0 +1,85 @@
+// Package payees links transaction descriptions to the user's payees.
+package payees
+
+import (
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/classifier"
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Normalize reduces a description or alias to lower-case, accent-free words
+// separated by single spaces, without numbers or punctuation, so
+// "UBER *TRIP 123" becomes "uber trip".
+func Normalize(text string) string {
+	return strings.Join(classifier.Words(text), " ")
+}
+
+type alias struct {
+	payeeID uuid.UUID
+	pattern string
+}
+
+// Matcher finds the payee of a description.
+type Matcher struct {
+	aliases []alias
+}
+
+// NewMatcher prepares the names and aliases of the payees. Aliases that
+// normalize to nothing (numbers only, for example) are ignored.
+func NewMatcher(payees []models.Payee) *Matcher {
+	matcher := &Matcher{}
+	for _, payee := range payees {
+		for _, text := range append([]string{payee.Name}, payee.Aliases...) {
+			if pattern := Normalize(text); pattern != "" {
+				matcher.aliases = append(matcher.aliases, alias{payeeID: payee.ID, pattern: pattern})
+			}
+		}
+	}
+	return matcher
+}
+
+// Match returns the payee whose name or alias appears in the description as
+// a sequence of whole words, or nil. When several do, the longest alias wins,
+// as it is the most specific.
+func (m *Matcher) Match(description string) *uuid.UUID {
+	text := " " + Normalize(description) + " "
+
+	var best *alias
+	for i := range m.aliases {
+		candidate := &m.aliases[i]
+		if !strings.Contains(text, " "+candidate.pattern+" ") {
+			continue
+		}
+		if best == nil || len(candidate.pattern) > len(best.pattern) {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+	payeeID := best.payeeID
+	return &payeeID
+}
+
+// Apply links the transaction to the payee matching its description, unless
+// it already has one and overwrite is false. It reports whether the payee
+// changed. Transfers are left alone.
+func (m *Matcher) Apply(transaction *models.Transaction, overwrite bool) bool {
+	if transaction.TransferID != nil || transaction.Description == nil {
+		return false
+	}
+	if transaction.PayeeID != nil && !overwrite {
+		return false
+	}
+
+	payeeID := m.Match(*transaction.Description)
+	if payeeID == nil || (transaction.PayeeID != nil && *transaction.PayeeID == *payeeID) {
+		return false
+	}
+
+	transaction.PayeeID = payeeID
+	return true
+}
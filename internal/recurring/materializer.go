@@ -0,0 +1,86 @@
+package recurring
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/alerts"
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+)
+
+// Materializer periodically creates the transactions of recurring rules that
+// have become due. Inserts are idempotent: a rule never produces two
+// transactions for the same date, so overlapping or repeated runs are safe.
+// The budget alerts are checked after creating expenses.
+type Materializer struct {
+	repo           *repository.RecurringRuleRepository
+	alertEvaluator *alerts.Evaluator
+	interval       time.Duration
+}
+
+func NewMaterializer(repo *repository.RecurringRuleRepository, alertEvaluator *alerts.Evaluator, interval time.Duration) *Materializer {
+	return &Materializer{
+		repo:           repo,
+		alertEvaluator: alertEvaluator,
+		interval:       interval,
+	}
+}
+
+// Start runs the materializer immediately and then on every tick until ctx is
+// cancelled. It is meant to be launched in its own goroutine.
+func (m *Materializer) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := m.RunOnce(time.Now())
+		if err != nil {
+			log.Printf("Recurring materializer failed: %v", err)
+		} else if created > 0 {
+			log.Printf("Recurring materializer created %d transactions", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce materializes every occurrence due on or before today and returns
+// how many transactions were created.
+func (m *Materializer) RunOnce(today time.Time) (int, error) {
+	today = truncateDay(today)
+
+	rules, err := m.repo.GetDue(today)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, rule := range rules {
+		from := rule.StartDate
+		if rule.LastRunDate != nil {
+			from = rule.LastRunDate.AddDate(0, 0, 1)
+		}
+
+		dates := Occurrences(rule, from, today, 0)
+
+		created, err := m.repo.Materialize(rule, dates, today)
+		if err != nil {
+			log.Printf("Failed to materialize recurring rule %s: %v", rule.ID, err)
+			continue
+		}
+		total += created
+
+		if created > 0 && rule.Type == "expense" {
+			if _, err := m.alertEvaluator.Check(rule.UserID, dates...); err != nil {
+				log.Printf("Budget alert check failed for recurring rule %s: %v", rule.ID, err)
+			}
+		}
+	}
+
+	return total, nil
+}
@@ -0,0 +1,105 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+// Occurrences returns the dates on which rule fires between from and to
+// (both inclusive, compared by calendar day). At most limit dates are
+// returned; a limit of zero means no limit.
+func Occurrences(rule models.RecurringRule, from, to time.Time, limit int) []time.Time {
+	start := truncateDay(rule.StartDate)
+	from = truncateDay(from)
+	to = truncateDay(to)
+
+	if rule.EndDate != nil && truncateDay(*rule.EndDate).Before(to) {
+		to = truncateDay(*rule.EndDate)
+	}
+
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var dates []time.Time
+	for step := firstStep(rule.Frequency, start, from, interval); ; step += interval {
+		date := nth(rule, start, step)
+		if date.After(to) {
+			break
+		}
+		if date.Before(start) || date.Before(from) {
+			continue
+		}
+
+		dates = append(dates, date)
+		if limit > 0 && len(dates) >= limit {
+			break
+		}
+	}
+
+	return dates
+}
+
+// firstStep skips the occurrences that certainly fall before from so rules
+// started years ago don't have to be walked from the beginning.
+func firstStep(frequency string, start, from time.Time, interval int) int {
+	if !from.After(start) {
+		return 0
+	}
+
+	var elapsed int
+	switch frequency {
+	case "daily":
+		elapsed = int(from.Sub(start).Hours() / 24)
+	case "weekly":
+		elapsed = int(from.Sub(start).Hours() / (24 * 7))
+	case "monthly":
+		elapsed = (from.Year()-start.Year())*12 + int(from.Month()-start.Month()) - 1
+	case "yearly":
+		elapsed = from.Year() - start.Year() - 1
+	}
+
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return elapsed / interval * interval
+}
+
+// nth returns the date n frequency units after start.
+func nth(rule models.RecurringRule, start time.Time, n int) time.Time {
+	switch rule.Frequency {
+	case "daily":
+		return start.AddDate(0, 0, n)
+	case "weekly":
+		return start.AddDate(0, 0, 7*n)
+	case "yearly":
+		return monthDay(start.Year()+n, start.Month(), dayOfMonth(rule, start))
+	default:
+		month := int(start.Month()) - 1 + n
+		return monthDay(start.Year()+month/12, time.Month(month%12+1), dayOfMonth(rule, start))
+	}
+}
+
+func dayOfMonth(rule models.RecurringRule, start time.Time) int {
+	if rule.DayOfMonth != nil {
+		return *rule.DayOfMonth
+	}
+	return start.Day()
+}
+
+// monthDay builds the date for day of the given month, clamping to the last
+// day of the month (e.g. day 31 in February becomes the 28th or 29th).
+func monthDay(year int, month time.Month, day int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,130 @@
+package recurring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestOccurrences(t *testing.T) {
+	day := func(d int) *int { return &d }
+	end := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name     string
+		rule     models.RecurringRule
+		from, to time.Time
+		limit    int
+		want     []time.Time
+	}{
+		{
+			name: "day 31 clamped in a leap year",
+			rule: models.RecurringRule{Frequency: "monthly", Interval: 1, StartDate: date(2024, 1, 31)},
+			from: date(2024, 1, 31), to: date(2024, 5, 31),
+			want: []time.Time{date(2024, 1, 31), date(2024, 2, 29), date(2024, 3, 31), date(2024, 4, 30), date(2024, 5, 31)},
+		},
+		{
+			name: "day 31 clamped in a common year",
+			rule: models.RecurringRule{Frequency: "monthly", Interval: 1, StartDate: date(2023, 1, 31)},
+			from: date(2023, 1, 1), to: date(2023, 3, 31),
+			want: []time.Time{date(2023, 1, 31), date(2023, 2, 28), date(2023, 3, 31)},
+		},
+		{
+			name: "day_of_month 30 overrides the start day",
+			rule: models.RecurringRule{Frequency: "monthly", Interval: 1, StartDate: date(2024, 1, 15), DayOfMonth: day(30)},
+			from: date(2024, 1, 15), to: date(2024, 3, 31),
+			want: []time.Time{date(2024, 1, 30), date(2024, 2, 29), date(2024, 3, 30)},
+		},
+		{
+			name: "day_of_month 29 in February of a common year",
+			rule: models.RecurringRule{Frequency: "monthly", Interval: 1, StartDate: date(2023, 1, 1), DayOfMonth: day(29)},
+			from: date(2023, 2, 1), to: date(2023, 3, 31),
+			want: []time.Time{date(2023, 2, 28), date(2023, 3, 29)},
+		},
+		{
+			name: "yearly on February 29",
+			rule: models.RecurringRule{Frequency: "yearly", Interval: 1, StartDate: date(2024, 2, 29)},
+			from: date(2024, 1, 1), to: date(2028, 3, 1),
+			want: []time.Time{date(2024, 2, 29), date(2025, 2, 28), date(2026, 2, 28), date(2027, 2, 28), date(2028, 2, 29)},
+		},
+		{
+			name: "every other week",
+			rule: models.RecurringRule{Frequency: "weekly", Interval: 2, StartDate: date(2024, 1, 1)},
+			from: date(2024, 1, 1), to: date(2024, 2, 1),
+			want: []time.Time{date(2024, 1, 1), date(2024, 1, 15), date(2024, 1, 29)},
+		},
+		{
+			name: "quarterly",
+			rule: models.RecurringRule{Frequency: "monthly", Interval: 3, StartDate: date(2024, 1, 10)},
+			from: date(2024, 1, 1), to: date(2024, 12, 31),
+			want: []time.Time{date(2024, 1, 10), date(2024, 4, 10), date(2024, 7, 10), date(2024, 10, 10)},
+		},
+		{
+			name: "zero interval means every unit",
+			rule: models.RecurringRule{Frequency: "daily", StartDate: date(2024, 1, 1)},
+			from: date(2024, 1, 1), to: date(2024, 1, 2),
+			want: []time.Time{date(2024, 1, 1), date(2024, 1, 2)},
+		},
+		{
+			name: "stops at end_date",
+			rule: models.RecurringRule{Frequency: "daily", Interval: 1, StartDate: date(2024, 1, 1), EndDate: end(date(2024, 1, 3))},
+			from: date(2024, 1, 1), to: date(2024, 1, 10),
+			want: []time.Time{date(2024, 1, 1), date(2024, 1, 2), date(2024, 1, 3)},
+		},
+		{
+			name: "end_date before the range",
+			rule: models.RecurringRule{Frequency: "daily", Interval: 1, StartDate: date(2024, 1, 1), EndDate: end(date(2024, 1, 3))},
+			from: date(2024, 2, 1), to: date(2024, 2, 10),
+		},
+		{
+			name: "nothing before start_date",
+			rule: models.RecurringRule{Frequency: "weekly", Interval: 1, StartDate: date(2024, 1, 10)},
+			from: date(2024, 1, 1), to: date(2024, 1, 20),
+			want: []time.Time{date(2024, 1, 10), date(2024, 1, 17)},
+		},
+		{
+			name: "catch-up after last_run_date",
+			rule: models.RecurringRule{Frequency: "monthly", Interval: 1, StartDate: date(2024, 1, 5), LastRunDate: end(date(2024, 2, 5))},
+			from: date(2024, 2, 6), to: date(2024, 5, 10),
+			want: []time.Time{date(2024, 3, 5), date(2024, 4, 5), date(2024, 5, 5)},
+		},
+		{
+			name: "catch-up of a rule started years ago",
+			rule: models.RecurringRule{Frequency: "monthly", Interval: 2, StartDate: date(2020, 1, 31), LastRunDate: end(date(2024, 1, 31))},
+			from: date(2024, 2, 1), to: date(2024, 7, 31),
+			want: []time.Time{date(2024, 3, 31), date(2024, 5, 31), date(2024, 7, 31)},
+		},
+		{
+			name: "times of day are ignored",
+			rule: models.RecurringRule{Frequency: "daily", Interval: 1, StartDate: time.Date(2024, 1, 1, 18, 30, 0, 0, time.UTC)},
+			from: time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC), to: time.Date(2024, 1, 3, 1, 0, 0, 0, time.UTC),
+			want: []time.Time{date(2024, 1, 2), date(2024, 1, 3)},
+		},
+		{
+			name: "limit",
+			rule: models.RecurringRule{Frequency: "daily", Interval: 1, StartDate: date(2024, 1, 1)},
+			from: date(2024, 1, 1), to: date(2024, 12, 31),
+			limit: 2,
+			want:  []time.Time{date(2024, 1, 1), date(2024, 1, 2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Occurrences(tt.rule, tt.from, tt.to, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("Occurrences = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
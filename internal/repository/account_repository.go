@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type AccountRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewAccountRepository(db *sql.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+func (r *AccountRepository) WithAudit(meta models.AuditMeta) *AccountRepository {
+	return &AccountRepository{db: r.db, audit: &meta}
+}
+
+func (r *AccountRepository) Create(account *models.Account) error {
+	query := `
+		INSERT INTO accounts (id, user_id, name, type, opening_balance, currency, archived, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	account.ID = uuid.New()
+	account.CreatedAt = time.Now()
+	account.UpdatedAt = time.Now()
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			account.ID,
+			account.UserID,
+			account.Name,
+			account.Type,
+			account.OpeningBalance,
+			account.Currency,
+			account.Archived,
+			account.CreatedAt,
+			account.UpdatedAt,
+		).Scan(&account.ID, &account.CreatedAt, &account.UpdatedAt)
+	})
+}
+
+func (r *AccountRepository) GetByID(id, userID uuid.UUID) (*models.Account, error) {
+	query := `
+		SELECT id, user_id, name, type, opening_balance, currency, archived, created_at, updated_at
+		FROM accounts
+		WHERE id = $1 AND user_id = $2
+	`
+
+	account := &models.Account{}
+	err := r.db.QueryRow(query, id, userID).Scan(
+		&account.ID,
+		&account.UserID,
+		&account.Name,
+		&account.Type,
+		&account.OpeningBalance,
+		&account.Currency,
+		&account.Archived,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("account not found")
+	}
+
+	return account, err
+}
+
+func (r *AccountRepository) GetAll(userID uuid.UUID, includeArchived bool) ([]models.Account, error) {
+	query := `
+		SELECT id, user_id, name, type, opening_balance, currency, archived, created_at, updated_at
+		FROM accounts
+		WHERE user_id = $1
+	`
+
+	if !includeArchived {
+		query += " AND archived = false"
+	}
+
+	query += " ORDER BY name ASC"
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.Account
+	for rows.Next() {
+		var account models.Account
+		if err := rows.Scan(
+			&account.ID,
+			&account.UserID,
+			&account.Name,
+			&account.Type,
+			&account.OpeningBalance,
+			&account.Currency,
+			&account.Archived,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *AccountRepository) Update(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	updates["updated_at"] = time.Now()
+
+	var setClauses []string
+	var args []interface{}
+	argPos := 1
+
+	for field, value := range updates {
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
+		args = append(args, value)
+		argPos++
+	}
+
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf(
+		"UPDATE accounts SET %s WHERE id = $%d AND user_id = $%d",
+		strings.Join(setClauses, ", "),
+		argPos,
+		argPos+1,
+	)
+
+	result, err := auditedExec(r.db, r.audit, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("account not found")
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) Delete(id, userID uuid.UUID) error {
+	query := "DELETE FROM accounts WHERE id = $1 AND user_id = $2"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("account not found")
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) GetBalance(id, userID uuid.UUID, asOf time.Time) (*models.AccountBalance, error) {
+	balances, err := accountBalances(r.db, userID, &id, asOf, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(balances) == 0 {
+		return nil, fmt.Errorf("account not found")
+	}
+
+	return &balances[0], nil
+}
+
+// accountBalances computes the balance of the user's accounts from the opening
+// balance plus every income and minus every expense dated on or before asOf,
+// converted to the account currency. Transactions in the trash do not count,
+// nor do those without an exchange rate, whose currencies are reported.
+// When accountID is nil all accounts are returned.
+func accountBalances(db *sql.DB, userID uuid.UUID, accountID *uuid.UUID, asOf time.Time, includeArchived bool) ([]models.AccountBalance, error) {
+	query := `
+		SELECT
+			a.id, a.name, a.currency, a.opening_balance,
+			COALESCE(SUM(CASE WHEN t.type = 'income' THEN fx_convert(t.amount, t.currency, a.currency, t.date) ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN t.type = 'expense' THEN fx_convert(t.amount, t.currency, a.currency, t.date) ELSE 0 END), 0) as expenses,
+			ARRAY_AGG(DISTINCT t.currency) FILTER (
+				WHERE t.id IS NOT NULL AND fx_convert(t.amount, t.currency, a.currency, t.date) IS NULL
+			) as missing_rates
+		FROM accounts a
+		LEFT JOIN transactions t ON t.account_id = a.id
+			AND t.user_id = a.user_id
+			AND t.date <= $2::date
+			AND t.deleted_at IS NULL
+		WHERE a.user_id = $1::uuid
+	`
+
+	args := []interface{}{userID, asOf}
+
+	if accountID != nil {
+		query += " AND a.id = $3::uuid"
+		args = append(args, *accountID)
+	}
+
+	if !includeArchived {
+		query += " AND a.archived = false"
+	}
+
+	query += " GROUP BY a.id, a.name, a.currency, a.opening_balance ORDER BY a.name ASC"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []models.AccountBalance
+	for rows.Next() {
+		var balance models.AccountBalance
+		if err := rows.Scan(
+			&balance.AccountID,
+			&balance.Name,
+			&balance.Currency,
+			&balance.OpeningBalance,
+			&balance.Income,
+			&balance.Expenses,
+			pq.Array(&balance.MissingRates),
+		); err != nil {
+			return nil, err
+		}
+
+		balance.Balance = balance.OpeningBalance + balance.Income - balance.Expenses
+		balance.AsOf = asOf.Format("2006-01-02")
+		balances = append(balances, balance)
+	}
+
+	return balances, rows.Err()
+}
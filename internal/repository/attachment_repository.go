@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+type AttachmentRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) WithAudit(meta models.AuditMeta) *AttachmentRepository {
+	return &AttachmentRepository{db: r.db, audit: &meta}
+}
+
+const attachmentColumns = `
+	id, user_id, transaction_id, filename, content_type, size, storage_key, created_at
+`
+
+// attachmentLive keeps out the attachments of transactions in the trash: they
+// come back with the transaction and their files go when it is purged.
+const attachmentLive = `
+	EXISTS (SELECT 1 FROM transactions t WHERE t.id = attachments.transaction_id AND t.deleted_at IS NULL)
+`
+
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.UserID,
+		&attachment.TransactionID,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// Create records an attachment whose ID and storage key were already set
+// when its file was stored.
+func (r *AttachmentRepository) Create(attachment *models.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, user_id, transaction_id, filename, content_type, size, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+
+	attachment.CreatedAt = time.Now()
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			attachment.ID,
+			attachment.UserID,
+			attachment.TransactionID,
+			attachment.Filename,
+			attachment.ContentType,
+			attachment.Size,
+			attachment.StorageKey,
+			attachment.CreatedAt,
+		).Scan(&attachment.CreatedAt)
+	})
+}
+
+func (r *AttachmentRepository) GetByID(id, transactionID, userID uuid.UUID) (*models.Attachment, error) {
+	query := "SELECT" + attachmentColumns + "FROM attachments WHERE id = $1 AND transaction_id = $2 AND user_id = $3 AND" + attachmentLive
+
+	attachment, err := scanAttachment(r.db.QueryRow(query, id, transactionID, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("attachment not found")
+	}
+
+	return attachment, err
+}
+
+// GetByTransaction lists the attachments of one transaction, oldest first.
+func (r *AttachmentRepository) GetByTransaction(transactionID, userID uuid.UUID) ([]models.Attachment, error) {
+	query := "SELECT" + attachmentColumns + `
+		FROM attachments
+		WHERE transaction_id = $1 AND user_id = $2 AND` + attachmentLive + `
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, transactionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) Delete(id, transactionID, userID uuid.UUID) error {
+	query := "DELETE FROM attachments WHERE id = $1 AND transaction_id = $2 AND user_id = $3 AND" + attachmentLive
+
+	result, err := auditedExec(r.db, r.audit, query, id, transactionID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("attachment not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// auditedBegin starts a DB transaction whose changes the audit triggers
+// attribute to the actor and request in meta. The settings are local to the
+// transaction, so they never leak to other uses of the connection.
+func auditedBegin(db *sql.DB, meta *models.AuditMeta) (*sql.Tx, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	if meta == nil {
+		return tx, nil
+	}
+
+	actorID := ""
+	if meta.ActorID != uuid.Nil {
+		actorID = meta.ActorID.String()
+	}
+
+	if _, err := tx.Exec(
+		`SELECT set_config('fintrack.actor_id', $1, true),
+			set_config('fintrack.request_id', $2, true),
+			set_config('fintrack.ip', $3, true)`,
+		actorID, meta.RequestID, meta.IP,
+	); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// auditedWrite runs fn inside an audited DB transaction, or straight on the
+// pool when there is no request to attribute the change to.
+func auditedWrite(db *sql.DB, meta *models.AuditMeta, fn func(q queryer) error) error {
+	if meta == nil {
+		return fn(db)
+	}
+
+	tx, err := auditedBegin(db, meta)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// auditedExec is db.Exec run through auditedWrite.
+func auditedExec(db *sql.DB, meta *models.AuditMeta, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := auditedWrite(db, meta, func(q queryer) error {
+		var err error
+		result, err = q.Exec(query, args...)
+		return err
+	})
+	return result, err
+}
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+const auditColumns = `
+	id, user_id, actor_id, entity_type, entity_id, action, before, after,
+	request_id, host(ip), created_at
+`
+
+func scanAuditEntry(row rowScanner) (*models.AuditEntry, error) {
+	entry := &models.AuditEntry{}
+	var before, after []byte
+	err := row.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.ActorID,
+		&entry.EntityType,
+		&entry.EntityID,
+		&entry.Action,
+		&before,
+		&after,
+		&entry.RequestID,
+		&entry.IP,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Before = before
+	entry.After = after
+	return entry, nil
+}
+
+func (r *AuditRepository) queryEntries(query string, args ...interface{}) ([]models.AuditEntry, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetByEntity returns the whole history of one record, newest first.
+func (r *AuditRepository) GetByEntity(userID uuid.UUID, entityType string, entityID uuid.UUID) ([]models.AuditEntry, error) {
+	query := "SELECT" + auditColumns + `
+		FROM audit_log
+		WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3
+		ORDER BY id DESC
+	`
+
+	return r.queryEntries(query, userID, entityType, entityID)
+}
+
+// GetFeed returns the user's changes across all records, newest first, and
+// whether there are older ones past this page.
+func (r *AuditRepository) GetFeed(userID uuid.UUID, filters models.AuditFilters) ([]models.AuditEntry, bool, error) {
+	query := "SELECT" + auditColumns + "FROM audit_log WHERE user_id = $1"
+	args := []interface{}{userID}
+	argPos := 2
+
+	if filters.EntityType != "" {
+		query += fmt.Sprintf(" AND entity_type = $%d", argPos)
+		args = append(args, filters.EntityType)
+		argPos++
+	}
+
+	if filters.EntityID != nil {
+		query += fmt.Sprintf(" AND entity_id = $%d", argPos)
+		args = append(args, *filters.EntityID)
+		argPos++
+	}
+
+	if filters.Action != "" {
+		query += fmt.Sprintf(" AND action = $%d", argPos)
+		args = append(args, filters.Action)
+		argPos++
+	}
+
+	if filters.After > 0 {
+		query += fmt.Sprintf(" AND id < $%d", argPos)
+		args = append(args, filters.After)
+		argPos++
+	}
+
+	// One extra row tells whether there is a next page.
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", argPos)
+	args = append(args, filters.Limit+1)
+
+	entries, err := r.queryEntries(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(entries) > filters.Limit
+	if hasMore {
+		entries = entries[:filters.Limit]
+	}
+
+	return entries, hasMore, nil
+}
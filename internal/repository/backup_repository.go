@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type BackupRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewBackupRepository(db *sql.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+func (r *BackupRepository) WithAudit(meta models.AuditMeta) *BackupRepository {
+	return &BackupRepository{db: r.db, audit: &meta}
+}
+
+// backupDeleteOrder lists the tables cleared by a replacing restore, children
+// first.
+var backupDeleteOrder = []string{
+	"transactions",
+	"budgets",
+	"budget_templates",
+	"recurring_rules",
+	"categorization_rules",
+	"payees",
+	"financial_goals",
+	"tags",
+	"accounts",
+	"categories",
+}
+
+// idMap assigns new IDs to the records of a backup, so a restore never
+// collides with existing rows, and translates the references between them.
+type idMap map[uuid.UUID]uuid.UUID
+
+func (m idMap) assign(old uuid.UUID) uuid.UUID {
+	if id, ok := m[old]; ok {
+		return id
+	}
+	id := uuid.New()
+	m[old] = id
+	return id
+}
+
+func (m idMap) ref(old *uuid.UUID) *uuid.UUID {
+	if old == nil {
+		return nil
+	}
+	id := m.assign(*old)
+	return &id
+}
+
+// Restore re-creates every record of a (validated) backup for the user with
+// new IDs, inside one DB transaction. With replace the user's current data is
+// deleted first; otherwise the backup is added to it, reusing existing tags
+// and payees with the same name. It returns how many records of each entity
+// were created and the storage keys of the attachments deleted along with
+// the replaced transactions, whose files the caller must remove.
+func (r *BackupRepository) Restore(userID uuid.UUID, data *models.BackupData, replace bool) (map[string]int, []string, error) {
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var storageKeys []string
+	if replace {
+		rows, err := tx.Query(`
+			SELECT a.storage_key
+			FROM attachments a
+			JOIN transactions t ON t.id = a.transaction_id
+			WHERE t.user_id = $1
+		`, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				rows.Close()
+				return nil, nil, err
+			}
+			storageKeys = append(storageKeys, key)
+		}
+		if err := rows.Close(); err != nil {
+			return nil, nil, err
+		}
+		if err := rows.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		for _, table := range backupDeleteOrder {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	counts := make(map[string]int)
+	categories, accounts, tags, payees, rules, transfers := idMap{}, idMap{}, idMap{}, idMap{}, idMap{}, idMap{}
+	now := time.Now()
+
+	if data.Settings != nil {
+		_, err := tx.Exec(`
+			INSERT INTO user_settings (user_id, base_currency, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+			ON CONFLICT (user_id) DO UPDATE
+			SET base_currency = EXCLUDED.base_currency, updated_at = EXCLUDED.updated_at
+		`, userID, data.Settings.BaseCurrency, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		counts["settings"] = 1
+	}
+
+	for _, category := range data.Categories {
+		_, err := tx.Exec(`
+			INSERT INTO categories (id, user_id, name, type, color, icon, created_at, deleted_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, categories.assign(category.ID), userID, category.Name, category.Type, category.Color, category.Icon,
+			category.CreatedAt, category.DeletedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	counts["categories"] = len(data.Categories)
+
+	for _, account := range data.Accounts {
+		_, err := tx.Exec(`
+			INSERT INTO accounts (id, user_id, name, type, opening_balance, currency, archived, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, accounts.assign(account.ID), userID, account.Name, account.Type, account.OpeningBalance,
+			account.Currency, account.Archived, account.CreatedAt, account.UpdatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	counts["accounts"] = len(data.Accounts)
+
+	for _, tag := range data.Tags {
+		var id uuid.UUID
+		err := tx.QueryRow(`
+			INSERT INTO tags (id, user_id, name, color, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		`, uuid.New(), userID, tag.Name, tag.Color, tag.CreatedAt).Scan(&id)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags[tag.ID] = id
+	}
+	counts["tags"] = len(data.Tags)
+
+	for _, payee := range data.Payees {
+		aliases := payee.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+
+		var id uuid.UUID
+		err := tx.QueryRow(`
+			INSERT INTO payees (id, user_id, name, aliases, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		`, uuid.New(), userID, payee.Name, pq.Array(aliases), payee.CreatedAt, payee.UpdatedAt).Scan(&id)
+		if err != nil {
+			return nil, nil, err
+		}
+		payees[payee.ID] = id
+	}
+	counts["payees"] = len(data.Payees)
+
+	for _, rule := range data.RecurringRules {
+		_, err := tx.Exec(`
+			INSERT INTO recurring_rules (
+				id, user_id, frequency, interval, start_date, end_date, day_of_month,
+				category_id, account_id, type, amount, description, active, last_run_date, created_at, updated_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		`, rules.assign(rule.ID), userID, rule.Frequency, rule.Interval, rule.StartDate, rule.EndDate, rule.DayOfMonth,
+			categories.ref(rule.CategoryID), accounts.ref(rule.AccountID), rule.Type, rule.Amount, rule.Description,
+			rule.Active, rule.LastRunDate, rule.CreatedAt, rule.UpdatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	counts["recurring_rules"] = len(data.RecurringRules)
+
+	for _, rule := range data.CategorizationRules {
+		tagIDs := make([]uuid.UUID, len(rule.SetTagIDs))
+		for i, tagID := range rule.SetTagIDs {
+			tagIDs[i] = tags[tagID]
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO categorization_rules (
+				id, user_id, name, priority, active, match_type, pattern, transaction_type,
+				min_amount, max_amount, set_category_id, set_tag_ids, set_description,
+				created_at, updated_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::uuid[], $13, $14, $15)
+		`, uuid.New(), userID, rule.Name, rule.Priority, rule.Active, rule.MatchType, rule.Pattern,
+			rule.TransactionType, rule.MinAmount, rule.MaxAmount, categories.ref(rule.SetCategoryID),
+			uuidArray(tagIDs), rule.SetDescription, rule.CreatedAt, rule.UpdatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	counts["categorization_rules"] = len(data.CategorizationRules)
+
+	for _, transaction := range data.Transactions {
+		id := uuid.New()
+		_, err := tx.Exec(`
+			INSERT INTO transactions (
+				id, user_id, category_id, account_id, payee_id, transfer_id, recurring_rule_id, type, amount, currency,
+				description, external_id, date, created_at, updated_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, `+currencyOrDefault("$10", "$4", "$2")+`, $11, $12, $13, $14, $15)
+		`, id, userID, categories.ref(transaction.CategoryID), accounts.ref(transaction.AccountID),
+			payees.ref(transaction.PayeeID), transfers.ref(transaction.TransferID), rules.ref(transaction.RecurringRuleID), transaction.Type,
+			transaction.Amount, transaction.Currency, transaction.Description, transaction.ExternalID,
+			transaction.Date, transaction.CreatedAt, transaction.UpdatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		splits := make([]models.TransactionSplit, len(transaction.Splits))
+		for i, split := range transaction.Splits {
+			splits[i] = models.TransactionSplit{
+				CategoryID: categories.ref(split.CategoryID),
+				Amount:     split.Amount,
+				Note:       split.Note,
+			}
+		}
+		if err := insertSplits(tx, id, splits); err != nil {
+			return nil, nil, err
+		}
+
+		for _, tag := range transaction.Tags {
+			if _, err := tx.Exec(
+				"INSERT INTO transaction_tags (transaction_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+				id, tags[tag.ID],
+			); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	counts["transactions"] = len(data.Transactions)
+
+	for _, budget := range data.Budgets {
+		thresholds := budget.AlertThresholds
+		if thresholds == nil {
+			thresholds = []int64{}
+		}
+		_, err := tx.Exec(`
+			INSERT INTO budgets (id, user_id, category_id, amount, month, rollover, alert_thresholds, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, uuid.New(), userID, categories.assign(budget.CategoryID), budget.Amount, budget.Month, budget.Rollover, pq.Array(thresholds), budget.CreatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	counts["budgets"] = len(data.Budgets)
+
+	for _, template := range data.BudgetTemplates {
+		items := make([]models.BudgetLine, len(template.Items))
+		for i, item := range template.Items {
+			items[i] = item
+			items[i].CategoryID = categories.assign(item.CategoryID)
+		}
+
+		encoded, err := json.Marshal(items)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO budget_templates (id, user_id, name, items, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New(), userID, template.Name, encoded, template.CreatedAt, template.UpdatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	counts["budget_templates"] = len(data.BudgetTemplates)
+
+	for _, goal := range data.Goals {
+		_, err := tx.Exec(`
+			INSERT INTO financial_goals (id, user_id, title, target_amount, current_amount, deadline, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, uuid.New(), userID, goal.Title, goal.TargetAmount, goal.CurrentAmount, goal.Deadline,
+			goal.Status, goal.CreatedAt, goal.UpdatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	counts["goals"] = len(data.Goals)
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return counts, storageKeys, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+type BudgetAlertRepository struct {
+	db *sql.DB
+}
+
+func NewBudgetAlertRepository(db *sql.DB) *BudgetAlertRepository {
+	return &BudgetAlertRepository{db: db}
+}
+
+// Record saves the alerts that were not raised yet for their budget, month
+// and threshold, and returns them with their ID set. The others are dropped.
+func (r *BudgetAlertRepository) Record(alerts []models.BudgetAlert) ([]models.BudgetAlert, error) {
+	query := `
+		INSERT INTO budget_alerts (
+			id, user_id, budget_id, category_id, month, threshold, available, spent, percentage, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (budget_id, month, threshold) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	var recorded []models.BudgetAlert
+	for _, alert := range alerts {
+		alert.ID = uuid.New()
+		alert.CreatedAt = time.Now()
+
+		err := r.db.QueryRow(
+			query,
+			alert.ID,
+			alert.UserID,
+			alert.BudgetID,
+			alert.CategoryID,
+			alert.Month,
+			alert.Threshold,
+			alert.Available,
+			alert.Spent,
+			alert.Percentage,
+			alert.CreatedAt,
+		).Scan(&alert.ID, &alert.CreatedAt)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		recorded = append(recorded, alert)
+	}
+
+	return recorded, nil
+}
+
+// GetAll returns the user's alerts, newest first, with their category.
+func (r *BudgetAlertRepository) GetAll(userID uuid.UUID, filters models.BudgetAlertFilters) ([]models.BudgetAlert, error) {
+	query := `
+		SELECT
+			a.id, a.user_id, a.budget_id, a.category_id, a.month, a.threshold,
+			a.available, a.spent, a.percentage, a.read_at, a.created_at,
+			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
+		FROM budget_alerts a
+		JOIN budgets b ON b.id = a.budget_id
+		JOIN categories c ON c.id = a.category_id
+		WHERE a.user_id = $1` + budgetLive + `
+	`
+
+	args := []interface{}{userID}
+
+	if filters.Month != nil {
+		query += " AND a.month = DATE_TRUNC('month', $2::date)"
+		args = append(args, *filters.Month)
+	}
+
+	if filters.Unread {
+		query += " AND a.read_at IS NULL"
+	}
+
+	query += " ORDER BY a.created_at DESC, a.threshold DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []models.BudgetAlert
+	for rows.Next() {
+		var alert models.BudgetAlert
+		var category models.Category
+
+		if err := rows.Scan(
+			&alert.ID,
+			&alert.UserID,
+			&alert.BudgetID,
+			&alert.CategoryID,
+			&alert.Month,
+			&alert.Threshold,
+			&alert.Available,
+			&alert.Spent,
+			&alert.Percentage,
+			&alert.ReadAt,
+			&alert.CreatedAt,
+			&category.ID,
+			&category.UserID,
+			&category.Name,
+			&category.Type,
+			&category.Color,
+			&category.Icon,
+			&category.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		alert.Category = &category
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// MarkRead marks an alert as read; alerts already read keep their read time.
+func (r *BudgetAlertRepository) MarkRead(id, userID uuid.UUID) error {
+	query := "UPDATE budget_alerts SET read_at = COALESCE(read_at, $3) WHERE id = $1 AND user_id = $2"
+
+	result, err := r.db.Exec(query, id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("budget alert not found")
+	}
+
+	return nil
+}
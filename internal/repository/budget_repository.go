@@ -7,45 +7,63 @@ import (
 
 	"github.com/Gildaciolopes/fintrack-api/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type BudgetRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	audit *models.AuditMeta
 }
 
 func NewBudgetRepository(db *sql.DB) *BudgetRepository {
 	return &BudgetRepository{db: db}
 }
 
+func (r *BudgetRepository) WithAudit(meta models.AuditMeta) *BudgetRepository {
+	return &BudgetRepository{db: r.db, audit: &meta}
+}
+
+// budgetLive leaves out budgets in the trash and budgets whose category is in
+// the trash; the latter come back when the category is restored.
+const budgetLive = `
+			AND b.deleted_at IS NULL AND c.deleted_at IS NULL`
+
 func (r *BudgetRepository) Create(budget *models.Budget) error {
 	query := `
-		INSERT INTO budgets (id, user_id, category_id, amount, month, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO budgets (id, user_id, category_id, amount, month, rollover, alert_thresholds, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at
 	`
 
 	budget.ID = uuid.New()
 	budget.CreatedAt = time.Now()
+	if budget.AlertThresholds == nil {
+		budget.AlertThresholds = []int64{}
+	}
 
-	return r.db.QueryRow(
-		query,
-		budget.ID,
-		budget.UserID,
-		budget.CategoryID,
-		budget.Amount,
-		budget.Month,
-		budget.CreatedAt,
-	).Scan(&budget.ID, &budget.CreatedAt)
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			budget.ID,
+			budget.UserID,
+			budget.CategoryID,
+			budget.Amount,
+			budget.Month,
+			budget.Rollover,
+			pq.Array(budget.AlertThresholds),
+			budget.CreatedAt,
+		).Scan(&budget.ID, &budget.CreatedAt)
+	})
 }
 
 func (r *BudgetRepository) GetByID(id, userID uuid.UUID) (*models.Budget, error) {
 	query := `
 		SELECT 
-			b.id, b.user_id, b.category_id, b.amount, b.month, b.created_at,
+			b.id, b.user_id, b.category_id, b.amount, b.month, b.rollover, b.alert_thresholds, b.created_at,
 			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
 		FROM budgets b
 		LEFT JOIN categories c ON b.category_id = c.id
-		WHERE b.id = $1 AND b.user_id = $2
+		WHERE b.id = $1 AND b.user_id = $2` + budgetLive + `
 	`
 
 	budget := &models.Budget{}
@@ -57,6 +75,8 @@ func (r *BudgetRepository) GetByID(id, userID uuid.UUID) (*models.Budget, error)
 		&budget.CategoryID,
 		&budget.Amount,
 		&budget.Month,
+		&budget.Rollover,
+		pq.Array(&budget.AlertThresholds),
 		&budget.CreatedAt,
 		&category.ID,
 		&category.UserID,
@@ -81,11 +101,11 @@ func (r *BudgetRepository) GetByID(id, userID uuid.UUID) (*models.Budget, error)
 func (r *BudgetRepository) GetAll(userID uuid.UUID, month *time.Time) ([]models.Budget, error) {
 	query := `
 		SELECT 
-			b.id, b.user_id, b.category_id, b.amount, b.month, b.created_at,
+			b.id, b.user_id, b.category_id, b.amount, b.month, b.rollover, b.alert_thresholds, b.created_at,
 			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
 		FROM budgets b
 		LEFT JOIN categories c ON b.category_id = c.id
-		WHERE b.user_id = $1
+		WHERE b.user_id = $1` + budgetLive + `
 	`
 
 	args := []interface{}{userID}
@@ -114,6 +134,8 @@ func (r *BudgetRepository) GetAll(userID uuid.UUID, month *time.Time) ([]models.
 			&budget.CategoryID,
 			&budget.Amount,
 			&budget.Month,
+			&budget.Rollover,
+			pq.Array(&budget.AlertThresholds),
 			&budget.CreatedAt,
 			&category.ID,
 			&category.UserID,
@@ -133,31 +155,60 @@ func (r *BudgetRepository) GetAll(userID uuid.UUID, month *time.Time) ([]models.
 	return budgets, rows.Err()
 }
 
+// GetBudgetsWithSpent returns the budgets of the month with what was spent in
+// their category. A budget with rollover carries what it left over, or its
+// overspend, into the next month's budget of the category, so the amount
+// carried over is computed along the chain of earlier months; a month without
+// a budget, or whose budget has no rollover, breaks the chain.
 func (r *BudgetRepository) GetBudgetsWithSpent(userID uuid.UUID, month time.Time) ([]models.BudgetWithSpent, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
-			b.id, b.user_id, b.category_id, b.amount, b.month, b.created_at,
+			b.id, b.user_id, b.category_id, b.amount, b.month, b.rollover, b.alert_thresholds, b.created_at,
 			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at,
-			COALESCE(SUM(t.amount), 0) as spent
+			COALESCE(SUM(fx_convert(t.amount, t.currency, $3, t.date)), 0) as spent,
+			ARRAY_AGG(DISTINCT t.currency) FILTER (
+				WHERE t.transaction_id IS NOT NULL AND fx_convert(t.amount, t.currency, $3, t.date) IS NULL
+			) as missing_rates
 		FROM budgets b
 		LEFT JOIN categories c ON b.category_id = c.id
-		LEFT JOIN transactions t ON t.category_id = b.category_id 
+		LEFT JOIN (` + transactionLines + `) t ON t.category_id = b.category_id 
 			AND t.user_id = b.user_id 
 			AND t.type = 'expense'
+			AND t.transfer_id IS NULL
 			AND DATE_TRUNC('month', t.date) = DATE_TRUNC('month', b.month)
 		WHERE b.user_id = $1 
-			AND DATE_TRUNC('month', b.month) = DATE_TRUNC('month', $2::date)
-		GROUP BY b.id, b.user_id, b.category_id, b.amount, b.month, b.created_at,
+			AND DATE_TRUNC('month', b.month) <= DATE_TRUNC('month', $2::date)
+			AND b.category_id IN (
+				SELECT category_id FROM budgets
+				WHERE user_id = $1 AND deleted_at IS NULL
+					AND DATE_TRUNC('month', month) = DATE_TRUNC('month', $2::date)
+			)` + budgetLive + `
+		GROUP BY b.id, b.user_id, b.category_id, b.amount, b.month, b.rollover, b.alert_thresholds, b.created_at,
 				 c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
-		ORDER BY c.name ASC
+		ORDER BY c.name ASC, b.category_id, DATE_TRUNC('month', b.month) ASC, b.created_at ASC
 	`
 
-	rows, err := r.db.Query(query, userID, month)
+	rows, err := r.db.Query(query, userID, month, currency)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	// The month's budgets come after the earlier budgets of their category,
+	// oldest first; previous is the last one seen per category.
+	type carry struct {
+		month    time.Time
+		left     models.Money
+		rollover bool
+	}
+	previous := make(map[uuid.UUID]carry)
+	target := startOfMonth(month)
+
 	var budgetsWithSpent []models.BudgetWithSpent
 	for rows.Next() {
 		var bws models.BudgetWithSpent
@@ -169,6 +220,8 @@ func (r *BudgetRepository) GetBudgetsWithSpent(userID uuid.UUID, month time.Time
 			&bws.CategoryID,
 			&bws.Amount,
 			&bws.Month,
+			&bws.Rollover,
+			pq.Array(&bws.AlertThresholds),
 			&bws.CreatedAt,
 			&category.ID,
 			&category.UserID,
@@ -178,30 +231,41 @@ func (r *BudgetRepository) GetBudgetsWithSpent(userID uuid.UUID, month time.Time
 			&category.Icon,
 			&category.CreatedAt,
 			&bws.Spent,
+			pq.Array(&bws.MissingRates),
 		); err != nil {
 			return nil, err
 		}
 
-		bws.Category = &category
-		bws.Remaining = bws.Amount - bws.Spent
-		if bws.Amount > 0 {
-			bws.Percentage = (bws.Spent / bws.Amount) * 100
+		start := startOfMonth(bws.Month)
+		if prev, ok := previous[bws.CategoryID]; ok && prev.rollover && prev.month.AddDate(0, 1, 0).Equal(start) {
+			bws.CarriedOver = prev.left
 		}
 
-		budgetsWithSpent = append(budgetsWithSpent, bws)
+		bws.Available = bws.Amount + bws.CarriedOver
+		bws.Remaining = bws.Available - bws.Spent
+		bws.Percentage = bws.Spent.Percent(bws.Available)
+		previous[bws.CategoryID] = carry{month: start, left: bws.Remaining, rollover: bws.Rollover}
+
+		if start.Equal(target) {
+			bws.Category = &category
+			budgetsWithSpent = append(budgetsWithSpent, bws)
+		}
 	}
 
 	return budgetsWithSpent, rows.Err()
 }
 
-func (r *BudgetRepository) Update(id, userID uuid.UUID, amount float64, month time.Time) error {
+// Update sets the amount, month, rollover and alert thresholds of a budget,
+// leaving each one unchanged when nil.
+func (r *BudgetRepository) Update(id, userID uuid.UUID, amount *models.Money, month *time.Time, rollover *bool, alertThresholds []int64) error {
 	query := `
 		UPDATE budgets 
-		SET amount = $1, month = $2
-		WHERE id = $3 AND user_id = $4
+		SET amount = COALESCE($1, amount), month = COALESCE($2, month), rollover = COALESCE($5, rollover),
+			alert_thresholds = COALESCE($6, alert_thresholds)
+		WHERE id = $3 AND user_id = $4 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, amount, month, id, userID)
+	result, err := auditedExec(r.db, r.audit, query, amount, month, id, userID, rollover, pq.Array(alertThresholds))
 	if err != nil {
 		return err
 	}
@@ -218,10 +282,11 @@ func (r *BudgetRepository) Update(id, userID uuid.UUID, amount float64, month ti
 	return nil
 }
 
+// Delete moves a budget to the trash.
 func (r *BudgetRepository) Delete(id, userID uuid.UUID) error {
-	query := "DELETE FROM budgets WHERE id = $1 AND user_id = $2"
+	query := "UPDATE budgets SET deleted_at = $3 WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
 
-	result, err := r.db.Exec(query, id, userID)
+	result, err := auditedExec(r.db, r.audit, query, id, userID, time.Now())
 	if err != nil {
 		return err
 	}
@@ -237,3 +302,244 @@ func (r *BudgetRepository) Delete(id, userID uuid.UUID) error {
 
 	return nil
 }
+
+// GetDeleted lists the budgets in the trash, most recently deleted first,
+// with their category even when it is in the trash too.
+func (r *BudgetRepository) GetDeleted(userID uuid.UUID) ([]models.Budget, error) {
+	query := `
+		SELECT 
+			b.id, b.user_id, b.category_id, b.amount, b.month, b.rollover, b.alert_thresholds, b.created_at, b.deleted_at,
+			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
+		FROM budgets b
+		LEFT JOIN categories c ON b.category_id = c.id
+		WHERE b.user_id = $1 AND b.deleted_at IS NOT NULL
+		ORDER BY b.deleted_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []models.Budget
+	for rows.Next() {
+		var budget models.Budget
+		var category models.Category
+
+		if err := rows.Scan(
+			&budget.ID,
+			&budget.UserID,
+			&budget.CategoryID,
+			&budget.Amount,
+			&budget.Month,
+			&budget.Rollover,
+			pq.Array(&budget.AlertThresholds),
+			&budget.CreatedAt,
+			&budget.DeletedAt,
+			&category.ID,
+			&category.UserID,
+			&category.Name,
+			&category.Type,
+			&category.Color,
+			&category.Icon,
+			&category.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		budget.Category = &category
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+// Restore takes a budget out of the trash. It stays hidden while its
+// category is in the trash.
+func (r *BudgetRepository) Restore(id, userID uuid.UUID) error {
+	query := "UPDATE budgets SET deleted_at = NULL WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotInTrash
+	}
+
+	return nil
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// GetLines returns the budgets of a month as lines to copy to another one.
+func (r *BudgetRepository) GetLines(userID uuid.UUID, month time.Time) ([]models.BudgetLine, error) {
+	query := `
+		SELECT b.category_id, b.amount, b.rollover, b.alert_thresholds
+		FROM budgets b
+		LEFT JOIN categories c ON b.category_id = c.id
+		WHERE b.user_id = $1
+			AND DATE_TRUNC('month', b.month) = DATE_TRUNC('month', $2::date)` + budgetLive + `
+		ORDER BY c.name ASC, b.created_at ASC
+	`
+
+	rows, err := r.db.Query(query, userID, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.BudgetLine
+	for rows.Next() {
+		var line models.BudgetLine
+		if err := rows.Scan(&line.CategoryID, &line.Amount, &line.Rollover, pq.Array(&line.AlertThresholds)); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// SpendAverage returns, per expense category, the average monthly spend over
+// the given number of months before month, in the base currency, as lines
+// for the budgets of month. Each line keeps the rollover and alert
+// thresholds of the category's budget in the previous month.
+func (r *BudgetRepository) SpendAverage(userID uuid.UUID, month time.Time, months int) ([]models.BudgetLine, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			t.category_id,
+			ROUND(SUM(fx_convert(t.amount, t.currency, $4, t.date)) / $3::int, 2) as average,
+			COALESCE((
+				SELECT BOOL_OR(pb.rollover)
+				FROM budgets pb
+				WHERE pb.user_id = $1
+					AND pb.category_id = t.category_id
+					AND pb.deleted_at IS NULL
+					AND DATE_TRUNC('month', pb.month) = DATE_TRUNC('month', $2::date) - INTERVAL '1 month'
+			), false) as rollover,
+			COALESCE((
+				SELECT pb.alert_thresholds
+				FROM budgets pb
+				WHERE pb.user_id = $1
+					AND pb.category_id = t.category_id
+					AND pb.deleted_at IS NULL
+					AND DATE_TRUNC('month', pb.month) = DATE_TRUNC('month', $2::date) - INTERVAL '1 month'
+				ORDER BY pb.created_at ASC
+				LIMIT 1
+			), '{}') as alert_thresholds
+		FROM (` + transactionLines + `) t
+		JOIN categories c ON c.id = t.category_id AND c.deleted_at IS NULL
+		WHERE t.user_id = $1
+			AND t.type = 'expense'
+			AND t.transfer_id IS NULL
+			AND t.date >= DATE_TRUNC('month', $2::date) - make_interval(months => $3::int)
+			AND t.date < DATE_TRUNC('month', $2::date)
+		GROUP BY t.category_id, c.name
+		HAVING SUM(fx_convert(t.amount, t.currency, $4, t.date)) > 0
+		ORDER BY c.name ASC
+	`
+
+	rows, err := r.db.Query(query, userID, month, months, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.BudgetLine
+	for rows.Next() {
+		var line models.BudgetLine
+		if err := rows.Scan(&line.CategoryID, &line.Amount, &line.Rollover, pq.Array(&line.AlertThresholds)); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// ApplyLines creates the budgets of a month from lines, in a single DB
+// transaction. When the category already has a budget in the month, the line
+// is skipped or, with overwrite, replaces its amount, rollover and alert
+// thresholds. Lines whose category does not exist or is in the trash are
+// skipped.
+func (r *BudgetRepository) ApplyLines(userID uuid.UUID, month time.Time, lines []models.BudgetLine, onConflict string) (*models.BudgetApplyResult, error) {
+	month = startOfMonth(month)
+	result := &models.BudgetApplyResult{Month: month.Format("2006-01-02")}
+
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existingQuery := `
+		SELECT b.id
+		FROM categories c
+		LEFT JOIN budgets b ON b.category_id = c.id
+			AND b.user_id = c.user_id
+			AND b.deleted_at IS NULL
+			AND DATE_TRUNC('month', b.month) = DATE_TRUNC('month', $3::date)
+		WHERE c.id = $2 AND c.user_id = $1 AND c.deleted_at IS NULL
+		ORDER BY b.created_at ASC
+		LIMIT 1
+	`
+
+	for _, line := range lines {
+		var existingID *uuid.UUID
+		err := tx.QueryRow(existingQuery, userID, line.CategoryID, month).Scan(&existingID)
+		if err == sql.ErrNoRows {
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		thresholds := line.AlertThresholds
+		if thresholds == nil {
+			thresholds = []int64{}
+		}
+
+		switch {
+		case existingID == nil:
+			if _, err := tx.Exec(`
+				INSERT INTO budgets (id, user_id, category_id, amount, month, rollover, alert_thresholds, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			`, uuid.New(), userID, line.CategoryID, line.Amount, month, line.Rollover, pq.Array(thresholds), time.Now()); err != nil {
+				return nil, err
+			}
+			result.Created++
+		case onConflict == models.BudgetConflictOverwrite:
+			if _, err := tx.Exec(
+				"UPDATE budgets SET amount = $1, rollover = $2, alert_thresholds = $3 WHERE id = $4",
+				line.Amount, line.Rollover, pq.Array(thresholds), *existingID,
+			); err != nil {
+				return nil, err
+			}
+			result.Updated++
+		default:
+			result.Skipped++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
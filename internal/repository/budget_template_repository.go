@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+type BudgetTemplateRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewBudgetTemplateRepository(db *sql.DB) *BudgetTemplateRepository {
+	return &BudgetTemplateRepository{db: db}
+}
+
+func (r *BudgetTemplateRepository) WithAudit(meta models.AuditMeta) *BudgetTemplateRepository {
+	return &BudgetTemplateRepository{db: r.db, audit: &meta}
+}
+
+const budgetTemplateColumns = `
+	id, user_id, name, items, created_at, updated_at
+`
+
+func scanBudgetTemplate(row rowScanner) (*models.BudgetTemplate, error) {
+	template := &models.BudgetTemplate{}
+	var items []byte
+	err := row.Scan(
+		&template.ID,
+		&template.UserID,
+		&template.Name,
+		&items,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(items, &template.Items); err != nil {
+		return nil, err
+	}
+
+	if template.Items == nil {
+		template.Items = []models.BudgetLine{}
+	}
+	return template, nil
+}
+
+func (r *BudgetTemplateRepository) Create(template *models.BudgetTemplate) error {
+	query := `
+		INSERT INTO budget_templates (id, user_id, name, items, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	items, err := json.Marshal(template.Items)
+	if err != nil {
+		return err
+	}
+
+	template.ID = uuid.New()
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			template.ID,
+			template.UserID,
+			template.Name,
+			items,
+			template.CreatedAt,
+			template.UpdatedAt,
+		).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	})
+}
+
+func (r *BudgetTemplateRepository) GetByID(id, userID uuid.UUID) (*models.BudgetTemplate, error) {
+	query := "SELECT" + budgetTemplateColumns + "FROM budget_templates WHERE id = $1 AND user_id = $2"
+
+	template, err := scanBudgetTemplate(r.db.QueryRow(query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("budget template not found")
+	}
+
+	return template, err
+}
+
+func (r *BudgetTemplateRepository) GetAll(userID uuid.UUID) ([]models.BudgetTemplate, error) {
+	query := "SELECT" + budgetTemplateColumns + "FROM budget_templates WHERE user_id = $1 ORDER BY name ASC"
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.BudgetTemplate
+	for rows.Next() {
+		template, err := scanBudgetTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *BudgetTemplateRepository) Update(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	updates["updated_at"] = time.Now()
+
+	var setClauses []string
+	var args []interface{}
+	argPos := 1
+
+	for field, value := range updates {
+		if items, ok := value.([]models.BudgetLine); ok {
+			encoded, err := json.Marshal(items)
+			if err != nil {
+				return err
+			}
+			value = encoded
+		}
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
+		args = append(args, value)
+		argPos++
+	}
+
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf(
+		"UPDATE budget_templates SET %s WHERE id = $%d AND user_id = $%d",
+		strings.Join(setClauses, ", "),
+		argPos,
+		argPos+1,
+	)
+
+	result, err := auditedExec(r.db, r.audit, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("budget template not found")
+	}
+
+	return nil
+}
+
+// Delete removes the template; budgets created from it are kept.
+func (r *BudgetTemplateRepository) Delete(id, userID uuid.UUID) error {
+	query := "DELETE FROM budget_templates WHERE id = $1 AND user_id = $2"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("budget template not found")
+	}
+
+	return nil
+}
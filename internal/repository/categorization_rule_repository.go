@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type CategorizationRuleRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewCategorizationRuleRepository(db *sql.DB) *CategorizationRuleRepository {
+	return &CategorizationRuleRepository{db: db}
+}
+
+func (r *CategorizationRuleRepository) WithAudit(meta models.AuditMeta) *CategorizationRuleRepository {
+	return &CategorizationRuleRepository{db: r.db, audit: &meta}
+}
+
+const categorizationRuleColumns = `
+	id, user_id, name, priority, active, match_type, pattern, transaction_type,
+	min_amount, max_amount, set_category_id, set_tag_ids, set_description,
+	created_at, updated_at
+`
+
+func scanCategorizationRule(row rowScanner) (*models.CategorizationRule, error) {
+	rule := &models.CategorizationRule{}
+	var tagIDs []string
+	err := row.Scan(
+		&rule.ID,
+		&rule.UserID,
+		&rule.Name,
+		&rule.Priority,
+		&rule.Active,
+		&rule.MatchType,
+		&rule.Pattern,
+		&rule.TransactionType,
+		&rule.MinAmount,
+		&rule.MaxAmount,
+		&rule.SetCategoryID,
+		pq.Array(&tagIDs),
+		&rule.SetDescription,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.SetTagIDs = make([]uuid.UUID, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		id, err := uuid.Parse(tagID)
+		if err != nil {
+			return nil, err
+		}
+		rule.SetTagIDs = append(rule.SetTagIDs, id)
+	}
+
+	return rule, nil
+}
+
+// uuidArray converts IDs to a value for UUID[] columns.
+func uuidArray(ids []uuid.UUID) interface{} {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return pq.Array(values)
+}
+
+func (r *CategorizationRuleRepository) Create(rule *models.CategorizationRule) error {
+	query := `
+		INSERT INTO categorization_rules (
+			id, user_id, name, priority, active, match_type, pattern, transaction_type,
+			min_amount, max_amount, set_category_id, set_tag_ids, set_description,
+			created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::uuid[], $13, $14, $15)
+		RETURNING id, created_at, updated_at
+	`
+
+	rule.ID = uuid.New()
+	rule.Active = true
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	if rule.SetTagIDs == nil {
+		rule.SetTagIDs = []uuid.UUID{}
+	}
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			rule.ID,
+			rule.UserID,
+			rule.Name,
+			rule.Priority,
+			rule.Active,
+			rule.MatchType,
+			rule.Pattern,
+			rule.TransactionType,
+			rule.MinAmount,
+			rule.MaxAmount,
+			rule.SetCategoryID,
+			uuidArray(rule.SetTagIDs),
+			rule.SetDescription,
+			rule.CreatedAt,
+			rule.UpdatedAt,
+		).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	})
+}
+
+func (r *CategorizationRuleRepository) GetByID(id, userID uuid.UUID) (*models.CategorizationRule, error) {
+	query := "SELECT" + categorizationRuleColumns + "FROM categorization_rules WHERE id = $1 AND user_id = $2"
+
+	rule, err := scanCategorizationRule(r.db.QueryRow(query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("categorization rule not found")
+	}
+
+	return rule, err
+}
+
+// GetAll returns the user's rules in evaluation order: highest priority
+// first, then oldest first.
+func (r *CategorizationRuleRepository) GetAll(userID uuid.UUID) ([]models.CategorizationRule, error) {
+	query := "SELECT" + categorizationRuleColumns + `
+		FROM categorization_rules
+		WHERE user_id = $1
+		ORDER BY priority DESC, created_at ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.CategorizationRule
+	for rows.Next() {
+		rule, err := scanCategorizationRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *CategorizationRuleRepository) Update(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	updates["updated_at"] = time.Now()
+
+	var setClauses []string
+	var args []interface{}
+	argPos := 1
+
+	for field, value := range updates {
+		if ids, ok := value.([]uuid.UUID); ok {
+			setClauses = append(setClauses, fmt.Sprintf("%s = $%d::uuid[]", field, argPos))
+			value = uuidArray(ids)
+		} else {
+			setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
+		}
+		args = append(args, value)
+		argPos++
+	}
+
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf(
+		"UPDATE categorization_rules SET %s WHERE id = $%d AND user_id = $%d",
+		strings.Join(setClauses, ", "),
+		argPos,
+		argPos+1,
+	)
+
+	result, err := auditedExec(r.db, r.audit, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("categorization rule not found")
+	}
+
+	return nil
+}
+
+func (r *CategorizationRuleRepository) Delete(id, userID uuid.UUID) error {
+	query := "DELETE FROM categorization_rules WHERE id = $1 AND user_id = $2"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("categorization rule not found")
+	}
+
+	return nil
+}
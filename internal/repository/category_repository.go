@@ -11,13 +11,18 @@ import (
 )
 
 type CategoryRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	audit *models.AuditMeta
 }
 
 func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
+func (r *CategoryRepository) WithAudit(meta models.AuditMeta) *CategoryRepository {
+	return &CategoryRepository{db: r.db, audit: &meta}
+}
+
 func (r *CategoryRepository) Create(category *models.Category) error {
 	query := `
 		INSERT INTO categories (id, user_id, name, type, color, icon, created_at)
@@ -28,23 +33,25 @@ func (r *CategoryRepository) Create(category *models.Category) error {
 	category.ID = uuid.New()
 	category.CreatedAt = time.Now()
 
-	return r.db.QueryRow(
-		query,
-		category.ID,
-		category.UserID,
-		category.Name,
-		category.Type,
-		category.Color,
-		category.Icon,
-		category.CreatedAt,
-	).Scan(&category.ID, &category.CreatedAt)
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			category.ID,
+			category.UserID,
+			category.Name,
+			category.Type,
+			category.Color,
+			category.Icon,
+			category.CreatedAt,
+		).Scan(&category.ID, &category.CreatedAt)
+	})
 }
 
 func (r *CategoryRepository) GetByID(id, userID uuid.UUID) (*models.Category, error) {
 	query := `
 		SELECT id, user_id, name, type, color, icon, created_at
 		FROM categories
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 
 	category := &models.Category{}
@@ -69,7 +76,7 @@ func (r *CategoryRepository) GetAll(userID uuid.UUID, categoryType string) ([]mo
 	query := `
 		SELECT id, user_id, name, type, color, icon, created_at
 		FROM categories
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 
 	args := []interface{}{userID}
@@ -125,13 +132,13 @@ func (r *CategoryRepository) Update(id, userID uuid.UUID, updates map[string]int
 	args = append(args, id, userID)
 
 	query := fmt.Sprintf(
-		"UPDATE categories SET %s WHERE id = $%d AND user_id = $%d",
+		"UPDATE categories SET %s WHERE id = $%d AND user_id = $%d AND deleted_at IS NULL",
 		strings.Join(setClauses, ", "),
 		argPos,
 		argPos+1,
 	)
 
-	result, err := r.db.Exec(query, args...)
+	result, err := auditedExec(r.db, r.audit, query, args...)
 	if err != nil {
 		return err
 	}
@@ -148,10 +155,13 @@ func (r *CategoryRepository) Update(id, userID uuid.UUID, updates map[string]int
 	return nil
 }
 
+// Delete moves a category to the trash. Its transactions and rules keep the
+// reference but read as uncategorized, and its budgets are hidden, until the
+// category is restored.
 func (r *CategoryRepository) Delete(id, userID uuid.UUID) error {
-	query := "DELETE FROM categories WHERE id = $1 AND user_id = $2"
+	query := "UPDATE categories SET deleted_at = $3 WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
 
-	result, err := r.db.Exec(query, id, userID)
+	result, err := auditedExec(r.db, r.audit, query, id, userID, time.Now())
 	if err != nil {
 		return err
 	}
@@ -167,3 +177,60 @@ func (r *CategoryRepository) Delete(id, userID uuid.UUID) error {
 
 	return nil
 }
+
+// GetDeleted lists the categories in the trash, most recently deleted first.
+func (r *CategoryRepository) GetDeleted(userID uuid.UUID) ([]models.Category, error) {
+	query := `
+		SELECT id, user_id, name, type, color, icon, created_at, deleted_at
+		FROM categories
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(
+			&category.ID,
+			&category.UserID,
+			&category.Name,
+			&category.Type,
+			&category.Color,
+			&category.Icon,
+			&category.CreatedAt,
+			&category.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// Restore takes a category out of the trash.
+func (r *CategoryRepository) Restore(id, userID uuid.UUID) error {
+	query := "UPDATE categories SET deleted_at = NULL WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotInTrash
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Gildaciolopes/fintrack-api/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type DashboardRepository struct {
@@ -16,25 +17,68 @@ func NewDashboardRepository(db *sql.DB) *DashboardRepository {
 	return &DashboardRepository{db: db}
 }
 
+// transactionLines yields one row per categorized amount: the transaction
+// itself when it has no splits, or each of its split lines otherwise. Category
+// reports select from it instead of transactions. Transactions in the trash
+// are left out.
+const transactionLines = `
+	SELECT t.id AS transaction_id, t.user_id, t.type, t.date, t.transfer_id, t.category_id, t.amount, t.currency
+	FROM transactions t
+	WHERE t.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
+	UNION ALL
+	SELECT t.id, t.user_id, t.type, t.date, t.transfer_id, s.category_id, s.amount, t.currency
+	FROM transaction_splits s
+	JOIN transactions t ON t.id = s.transaction_id
+	WHERE t.deleted_at IS NULL
+`
+
+// baseCurrency returns the currency the user's aggregates are reported in.
+func baseCurrency(q queryer, userID uuid.UUID) (string, error) {
+	var currency string
+	err := q.QueryRow("SELECT base_currency FROM user_settings WHERE user_id = $1", userID).Scan(&currency)
+	if err == sql.ErrNoRows {
+		return models.DefaultCurrency, nil
+	}
+	return currency, err
+}
+
 func (r *DashboardRepository) GetStats(userID uuid.UUID, startDate, endDate time.Time) (*models.DashboardStats, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
-			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as total_income,
-			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as total_expenses
+			COALESCE(SUM(CASE WHEN type = 'income' THEN fx_convert(amount, currency, $4, date) ELSE 0 END), 0) as total_income,
+			COALESCE(SUM(CASE WHEN type = 'expense' THEN fx_convert(amount, currency, $4, date) ELSE 0 END), 0) as total_expenses,
+			ARRAY_AGG(DISTINCT currency) FILTER (WHERE fx_convert(amount, currency, $4, date) IS NULL) as missing_rates
 		FROM transactions
 		WHERE user_id = $1::uuid AND date >= $2::date AND date <= $3::date
+			AND transfer_id IS NULL
+			AND deleted_at IS NULL
 	`
 
-	var totalIncome, totalExpenses float64
-	err := r.db.QueryRow(query, userID, startDate, endDate).Scan(&totalIncome, &totalExpenses)
+	var totalIncome, totalExpenses models.Money
+	var missingRates []string
+	err = r.db.QueryRow(query, userID, startDate, endDate, currency).Scan(&totalIncome, &totalExpenses, pq.Array(&missingRates))
 	if err != nil {
 		return nil, err
 	}
+	if missingRates == nil {
+		missingRates = []string{}
+	}
 
 	balance := totalIncome - totalExpenses
-	savingsRate := 0.0
-	if totalIncome > 0 {
-		savingsRate = (balance / totalIncome) * 100
+	savingsRate := balance.Percent(totalIncome)
+
+	accounts, err := accountBalances(r.db, userID, nil, endDate, false)
+	if err != nil {
+		return nil, err
+	}
+	if accounts == nil {
+		accounts = []models.AccountBalance{}
 	}
 
 	return &models.DashboardStats{
@@ -42,33 +86,42 @@ func (r *DashboardRepository) GetStats(userID uuid.UUID, startDate, endDate time
 		TotalExpenses: totalExpenses,
 		Balance:       balance,
 		SavingsRate:   savingsRate,
+		Currency:      currency,
+		MissingRates:  missingRates,
+		Accounts:      accounts,
 	}, nil
 }
 
 func (r *DashboardRepository) GetExpensesByCategory(userID uuid.UUID, startDate, endDate time.Time) ([]models.CategoryExpense, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
 			COALESCE(c.name, 'Sem categoria') as category,
-			SUM(t.amount) as amount,
+			SUM(fx_convert(t.amount, t.currency, $4, t.date)) as amount,
 			COALESCE(c.color, '#6366f1') as color
-		FROM transactions t
-		LEFT JOIN categories c ON t.category_id = c.id
+		FROM (` + transactionLines + `) t
+		LEFT JOIN categories c ON t.category_id = c.id AND c.deleted_at IS NULL
 		WHERE t.user_id = $1::uuid 
 			AND t.type = 'expense'
+			AND t.transfer_id IS NULL
 			AND t.date >= $2::date
 			AND t.date <= $3::date
 		GROUP BY c.name, c.color
 		ORDER BY amount DESC
 	`
 
-	rows, err := r.db.Query(query, userID, startDate, endDate)
+	rows, err := r.db.Query(query, userID, startDate, endDate, currency)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	var expenses []models.CategoryExpense
-	var totalAmount float64
+	var totalAmount models.Money
 
 	for rows.Next() {
 		var exp models.CategoryExpense
@@ -81,28 +134,33 @@ func (r *DashboardRepository) GetExpensesByCategory(userID uuid.UUID, startDate,
 
 	// Calculate percentages
 	for i := range expenses {
-		if totalAmount > 0 {
-			expenses[i].Percentage = (expenses[i].Amount / totalAmount) * 100
-		}
+		expenses[i].Percentage = expenses[i].Amount.Percent(totalAmount)
 	}
 
 	return expenses, rows.Err()
 }
 
 func (r *DashboardRepository) GetMonthlyData(userID uuid.UUID, months int) ([]models.MonthlyData, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
 			TO_CHAR(date, 'YYYY-MM') as month,
-			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
-			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expenses
+			COALESCE(SUM(CASE WHEN type = 'income' THEN fx_convert(amount, currency, $3, date) ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN type = 'expense' THEN fx_convert(amount, currency, $3, date) ELSE 0 END), 0) as expenses
 		FROM transactions
 		    WHERE user_id = $1::uuid 
 			    AND date >= DATE_TRUNC('month', CURRENT_DATE) - make_interval(months => $2::int - 1)
+			    AND transfer_id IS NULL
+			    AND deleted_at IS NULL
 		GROUP BY TO_CHAR(date, 'YYYY-MM')
 		ORDER BY month ASC
 	`
 
-	rows, err := r.db.Query(query, userID, months)
+	rows, err := r.db.Query(query, userID, months, currency)
 	if err != nil {
 		return nil, err
 	}
@@ -121,20 +179,27 @@ func (r *DashboardRepository) GetMonthlyData(userID uuid.UUID, months int) ([]mo
 }
 
 func (r *DashboardRepository) GetDailyData(userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyData, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
 			TO_CHAR(date, 'YYYY-MM-DD') as date_str,
-			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
-			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expenses
+			COALESCE(SUM(CASE WHEN type = 'income' THEN fx_convert(amount, currency, $4, date) ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN type = 'expense' THEN fx_convert(amount, currency, $4, date) ELSE 0 END), 0) as expenses
 		FROM transactions
 		WHERE user_id = $1::uuid 
 			AND date >= $2::date
 			AND date <= $3::date
+			AND transfer_id IS NULL
+			AND deleted_at IS NULL
 		GROUP BY date, TO_CHAR(date, 'YYYY-MM-DD')
 		ORDER BY date ASC
 	`
 
-	rows, err := r.db.Query(query, userID, startDate, endDate)
+	rows, err := r.db.Query(query, userID, startDate, endDate, currency)
 	if err != nil {
 		return nil, err
 	}
@@ -151,3 +216,117 @@ func (r *DashboardRepository) GetDailyData(userID uuid.UUID, startDate, endDate
 
 	return dailyData, rows.Err()
 }
+
+func (r *DashboardRepository) GetTagSummary(userID uuid.UUID, startDate, endDate time.Time) ([]models.TagSummary, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT 
+			tg.id, tg.name, tg.color,
+			COALESCE(SUM(CASE WHEN t.type = 'income' THEN fx_convert(t.amount, t.currency, $4, t.date) ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN t.type = 'expense' THEN fx_convert(t.amount, t.currency, $4, t.date) ELSE 0 END), 0) as expenses,
+			COUNT(t.id) as count
+		FROM tags tg
+		JOIN transaction_tags tt ON tt.tag_id = tg.id
+		JOIN transactions t ON t.id = tt.transaction_id
+		WHERE tg.user_id = $1::uuid
+			AND t.transfer_id IS NULL
+			AND t.deleted_at IS NULL
+			AND t.date >= $2::date
+			AND t.date <= $3::date
+		GROUP BY tg.id, tg.name, tg.color
+		ORDER BY expenses DESC, income DESC
+	`
+
+	rows, err := r.db.Query(query, userID, startDate, endDate, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []models.TagSummary
+	for rows.Next() {
+		var summary models.TagSummary
+		if err := rows.Scan(
+			&summary.TagID,
+			&summary.Tag,
+			&summary.Color,
+			&summary.Income,
+			&summary.Expenses,
+			&summary.Count,
+		); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// GetTopPayees ranks the payees by the total, in the user base currency, of
+// their transactions of the given type in the period, or by how many there
+// were when byCount is set. Transactions without a rate are counted but left
+// out of the total and the average.
+func (r *DashboardRepository) GetTopPayees(userID uuid.UUID, startDate, endDate time.Time, transactionType string, byCount bool, limit int) ([]models.PayeeSummary, error) {
+	currency, err := baseCurrency(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := "amount DESC, count DESC"
+	if byCount {
+		orderBy = "count DESC, amount DESC"
+	}
+
+	query := `
+		SELECT 
+			p.id, p.name,
+			COALESCE(SUM(fx_convert(t.amount, t.currency, $5, t.date)), 0) as amount,
+			COUNT(t.id) as count,
+			COUNT(fx_convert(t.amount, t.currency, $5, t.date)) as converted,
+			ARRAY_AGG(DISTINCT t.currency) FILTER (WHERE fx_convert(t.amount, t.currency, $5, t.date) IS NULL) as missing_rates
+		FROM payees p
+		JOIN transactions t ON t.payee_id = p.id
+		WHERE p.user_id = $1::uuid
+			AND t.user_id = $1::uuid
+			AND t.transfer_id IS NULL
+			AND t.deleted_at IS NULL
+			AND t.type = $4
+			AND t.date >= $2::date
+			AND t.date <= $3::date
+		GROUP BY p.id, p.name
+		ORDER BY ` + orderBy + `, p.name ASC
+		LIMIT $6
+	`
+
+	rows, err := r.db.Query(query, userID, startDate, endDate, transactionType, currency, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []models.PayeeSummary
+	for rows.Next() {
+		var summary models.PayeeSummary
+		var converted int64
+		if err := rows.Scan(
+			&summary.PayeeID,
+			&summary.Payee,
+			&summary.Amount,
+			&summary.Count,
+			&converted,
+			pq.Array(&summary.MissingRates),
+		); err != nil {
+			return nil, err
+		}
+		if converted > 0 {
+			summary.Average = summary.Amount / models.Money(converted)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
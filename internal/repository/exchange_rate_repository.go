@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+)
+
+type ExchangeRateRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// Upsert stores the rates in a single transaction, replacing any rate already
+// imported for the same currency and date.
+func (r *ExchangeRateRepository) Upsert(rates []models.ExchangeRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO exchange_rates (currency, rate_date, rate, source)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (currency, rate_date) DO UPDATE
+		SET rate = EXCLUDED.rate, source = EXCLUDED.source
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, rate := range rates {
+		if _, err := stmt.Exec(rate.Currency, rate.Date, rate.Rate, rate.Source); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
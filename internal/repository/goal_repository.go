@@ -11,13 +11,18 @@ import (
 )
 
 type GoalRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	audit *models.AuditMeta
 }
 
 func NewGoalRepository(db *sql.DB) *GoalRepository {
 	return &GoalRepository{db: db}
 }
 
+func (r *GoalRepository) WithAudit(meta models.AuditMeta) *GoalRepository {
+	return &GoalRepository{db: r.db, audit: &meta}
+}
+
 func (r *GoalRepository) Create(goal *models.FinancialGoal) error {
 	query := `
 		INSERT INTO financial_goals (id, user_id, title, target_amount, current_amount, deadline, status, created_at, updated_at)
@@ -30,25 +35,27 @@ func (r *GoalRepository) Create(goal *models.FinancialGoal) error {
 	goal.UpdatedAt = time.Now()
 	goal.Status = "active"
 
-	return r.db.QueryRow(
-		query,
-		goal.ID,
-		goal.UserID,
-		goal.Title,
-		goal.TargetAmount,
-		goal.CurrentAmount,
-		goal.Deadline,
-		goal.Status,
-		goal.CreatedAt,
-		goal.UpdatedAt,
-	).Scan(&goal.ID, &goal.CreatedAt, &goal.UpdatedAt)
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			goal.ID,
+			goal.UserID,
+			goal.Title,
+			goal.TargetAmount,
+			goal.CurrentAmount,
+			goal.Deadline,
+			goal.Status,
+			goal.CreatedAt,
+			goal.UpdatedAt,
+		).Scan(&goal.ID, &goal.CreatedAt, &goal.UpdatedAt)
+	})
 }
 
 func (r *GoalRepository) GetByID(id, userID uuid.UUID) (*models.FinancialGoal, error) {
 	query := `
 		SELECT id, user_id, title, target_amount, current_amount, deadline, status, created_at, updated_at
 		FROM financial_goals
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 
 	goal := &models.FinancialGoal{}
@@ -75,7 +82,7 @@ func (r *GoalRepository) GetAll(userID uuid.UUID, status string) ([]models.Finan
 	query := `
 		SELECT id, user_id, title, target_amount, current_amount, deadline, status, created_at, updated_at
 		FROM financial_goals
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 
 	args := []interface{}{userID}
@@ -135,13 +142,13 @@ func (r *GoalRepository) Update(id, userID uuid.UUID, updates map[string]interfa
 	args = append(args, id, userID)
 
 	query := fmt.Sprintf(
-		"UPDATE financial_goals SET %s WHERE id = $%d AND user_id = $%d",
+		"UPDATE financial_goals SET %s WHERE id = $%d AND user_id = $%d AND deleted_at IS NULL",
 		strings.Join(setClauses, ", "),
 		argPos,
 		argPos+1,
 	)
 
-	result, err := r.db.Exec(query, args...)
+	result, err := auditedExec(r.db, r.audit, query, args...)
 	if err != nil {
 		return err
 	}
@@ -158,10 +165,11 @@ func (r *GoalRepository) Update(id, userID uuid.UUID, updates map[string]interfa
 	return nil
 }
 
+// Delete moves a goal to the trash.
 func (r *GoalRepository) Delete(id, userID uuid.UUID) error {
-	query := "DELETE FROM financial_goals WHERE id = $1 AND user_id = $2"
+	query := "UPDATE financial_goals SET deleted_at = $3 WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
 
-	result, err := r.db.Exec(query, id, userID)
+	result, err := auditedExec(r.db, r.audit, query, id, userID, time.Now())
 	if err != nil {
 		return err
 	}
@@ -178,7 +186,7 @@ func (r *GoalRepository) Delete(id, userID uuid.UUID) error {
 	return nil
 }
 
-func (r *GoalRepository) Contribute(id, userID uuid.UUID, amount float64) error {
+func (r *GoalRepository) Contribute(id, userID uuid.UUID, amount models.Money) error {
 	query := `
 		UPDATE financial_goals 
 		SET current_amount = current_amount + $1, 
@@ -187,10 +195,10 @@ func (r *GoalRepository) Contribute(id, userID uuid.UUID, amount float64) error
 		        WHEN current_amount + $1 >= target_amount THEN 'completed'
 		        ELSE status 
 		    END
-		WHERE id = $3 AND user_id = $4
+		WHERE id = $3 AND user_id = $4 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, amount, time.Now(), id, userID)
+	result, err := auditedExec(r.db, r.audit, query, amount, time.Now(), id, userID)
 	if err != nil {
 		return err
 	}
@@ -206,3 +214,62 @@ func (r *GoalRepository) Contribute(id, userID uuid.UUID, amount float64) error
 
 	return nil
 }
+
+// GetDeleted lists the goals in the trash, most recently deleted first.
+func (r *GoalRepository) GetDeleted(userID uuid.UUID) ([]models.FinancialGoal, error) {
+	query := `
+		SELECT id, user_id, title, target_amount, current_amount, deadline, status, created_at, updated_at, deleted_at
+		FROM financial_goals
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []models.FinancialGoal
+	for rows.Next() {
+		var goal models.FinancialGoal
+		if err := rows.Scan(
+			&goal.ID,
+			&goal.UserID,
+			&goal.Title,
+			&goal.TargetAmount,
+			&goal.CurrentAmount,
+			&goal.Deadline,
+			&goal.Status,
+			&goal.CreatedAt,
+			&goal.UpdatedAt,
+			&goal.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		goals = append(goals, goal)
+	}
+
+	return goals, rows.Err()
+}
+
+// Restore takes a goal out of the trash.
+func (r *GoalRepository) Restore(id, userID uuid.UUID) error {
+	query := "UPDATE financial_goals SET deleted_at = NULL WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotInTrash
+	}
+
+	return nil
+}
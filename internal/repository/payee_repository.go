@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PayeeRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewPayeeRepository(db *sql.DB) *PayeeRepository {
+	return &PayeeRepository{db: db}
+}
+
+func (r *PayeeRepository) WithAudit(meta models.AuditMeta) *PayeeRepository {
+	return &PayeeRepository{db: r.db, audit: &meta}
+}
+
+const payeeColumns = `
+	id, user_id, name, aliases, created_at, updated_at
+`
+
+func scanPayee(row rowScanner) (*models.Payee, error) {
+	payee := &models.Payee{}
+	err := row.Scan(
+		&payee.ID,
+		&payee.UserID,
+		&payee.Name,
+		pq.Array(&payee.Aliases),
+		&payee.CreatedAt,
+		&payee.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if payee.Aliases == nil {
+		payee.Aliases = []string{}
+	}
+	return payee, nil
+}
+
+func (r *PayeeRepository) Create(payee *models.Payee) error {
+	query := `
+		INSERT INTO payees (id, user_id, name, aliases, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	payee.ID = uuid.New()
+	payee.CreatedAt = time.Now()
+	payee.UpdatedAt = time.Now()
+	if payee.Aliases == nil {
+		payee.Aliases = []string{}
+	}
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			payee.ID,
+			payee.UserID,
+			payee.Name,
+			pq.Array(payee.Aliases),
+			payee.CreatedAt,
+			payee.UpdatedAt,
+		).Scan(&payee.ID, &payee.CreatedAt, &payee.UpdatedAt)
+	})
+}
+
+func (r *PayeeRepository) GetByID(id, userID uuid.UUID) (*models.Payee, error) {
+	query := "SELECT" + payeeColumns + "FROM payees WHERE id = $1 AND user_id = $2"
+
+	payee, err := scanPayee(r.db.QueryRow(query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("payee not found")
+	}
+
+	return payee, err
+}
+
+func (r *PayeeRepository) GetAll(userID uuid.UUID) ([]models.Payee, error) {
+	query := "SELECT" + payeeColumns + "FROM payees WHERE user_id = $1 ORDER BY name ASC"
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payees []models.Payee
+	for rows.Next() {
+		payee, err := scanPayee(rows)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, *payee)
+	}
+
+	return payees, rows.Err()
+}
+
+func (r *PayeeRepository) Update(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	updates["updated_at"] = time.Now()
+
+	var setClauses []string
+	var args []interface{}
+	argPos := 1
+
+	for field, value := range updates {
+		if aliases, ok := value.([]string); ok {
+			value = pq.Array(aliases)
+		}
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
+		args = append(args, value)
+		argPos++
+	}
+
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf(
+		"UPDATE payees SET %s WHERE id = $%d AND user_id = $%d",
+		strings.Join(setClauses, ", "),
+		argPos,
+		argPos+1,
+	)
+
+	result, err := auditedExec(r.db, r.audit, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("payee not found")
+	}
+
+	return nil
+}
+
+// Delete removes the payee; its transactions are kept without a payee.
+func (r *PayeeRepository) Delete(id, userID uuid.UUID) error {
+	query := "DELETE FROM payees WHERE id = $1 AND user_id = $2"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("payee not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+type RecurringRuleRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewRecurringRuleRepository(db *sql.DB) *RecurringRuleRepository {
+	return &RecurringRuleRepository{db: db}
+}
+
+func (r *RecurringRuleRepository) WithAudit(meta models.AuditMeta) *RecurringRuleRepository {
+	return &RecurringRuleRepository{db: r.db, audit: &meta}
+}
+
+const recurringRuleColumns = `
+	id, user_id, frequency, interval, start_date, end_date, day_of_month,
+	category_id, account_id, type, amount, description, active, last_run_date,
+	created_at, updated_at
+`
+
+func scanRecurringRule(row rowScanner) (*models.RecurringRule, error) {
+	rule := &models.RecurringRule{}
+	err := row.Scan(
+		&rule.ID,
+		&rule.UserID,
+		&rule.Frequency,
+		&rule.Interval,
+		&rule.StartDate,
+		&rule.EndDate,
+		&rule.DayOfMonth,
+		&rule.CategoryID,
+		&rule.AccountID,
+		&rule.Type,
+		&rule.Amount,
+		&rule.Description,
+		&rule.Active,
+		&rule.LastRunDate,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *RecurringRuleRepository) Create(rule *models.RecurringRule) error {
+	query := `
+		INSERT INTO recurring_rules (
+			id, user_id, frequency, interval, start_date, end_date, day_of_month,
+			category_id, account_id, type, amount, description, active, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, created_at, updated_at
+	`
+
+	rule.ID = uuid.New()
+	rule.Active = true
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			rule.ID,
+			rule.UserID,
+			rule.Frequency,
+			rule.Interval,
+			rule.StartDate,
+			rule.EndDate,
+			rule.DayOfMonth,
+			rule.CategoryID,
+			rule.AccountID,
+			rule.Type,
+			rule.Amount,
+			rule.Description,
+			rule.Active,
+			rule.CreatedAt,
+			rule.UpdatedAt,
+		).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	})
+}
+
+func (r *RecurringRuleRepository) GetByID(id, userID uuid.UUID) (*models.RecurringRule, error) {
+	query := "SELECT" + recurringRuleColumns + "FROM recurring_rules WHERE id = $1 AND user_id = $2"
+
+	rule, err := scanRecurringRule(r.db.QueryRow(query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recurring rule not found")
+	}
+
+	return rule, err
+}
+
+func (r *RecurringRuleRepository) GetAll(userID uuid.UUID) ([]models.RecurringRule, error) {
+	query := "SELECT" + recurringRuleColumns + "FROM recurring_rules WHERE user_id = $1 ORDER BY start_date ASC"
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.RecurringRule
+	for rows.Next() {
+		rule, err := scanRecurringRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// GetDue returns the active rules, across all users, that have not been
+// materialized up to today yet.
+func (r *RecurringRuleRepository) GetDue(today time.Time) ([]models.RecurringRule, error) {
+	query := "SELECT" + recurringRuleColumns + `
+		FROM recurring_rules
+		WHERE active = true
+			AND start_date <= $1::date
+			AND (last_run_date IS NULL OR last_run_date < $1::date)
+			AND (end_date IS NULL OR last_run_date IS NULL OR last_run_date < end_date)
+	`
+
+	rows, err := r.db.Query(query, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.RecurringRule
+	for rows.Next() {
+		rule, err := scanRecurringRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// Materialize creates one transaction per date from the rule template and
+// records runDate as the last run, all inside a single DB transaction.
+// Dates that were already materialized are skipped.
+func (r *RecurringRuleRepository) Materialize(rule models.RecurringRule, dates []time.Time, runDate time.Time) (int, error) {
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO transactions (
+			id, user_id, category_id, account_id, recurring_rule_id, type, amount, currency, description, date, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, ` + currencyOrDefault("''", "$4", "$2") + `, $8, $9, $10, $11)
+		ON CONFLICT (recurring_rule_id, date) WHERE recurring_rule_id IS NOT NULL DO NOTHING
+	`
+
+	created := 0
+	for _, date := range dates {
+		now := time.Now()
+		result, err := tx.Exec(
+			query,
+			uuid.New(),
+			rule.UserID,
+			rule.CategoryID,
+			rule.AccountID,
+			rule.ID,
+			rule.Type,
+			rule.Amount,
+			rule.Description,
+			date,
+			now,
+			now,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		created += int(rows)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE recurring_rules SET last_run_date = $1 WHERE id = $2",
+		runDate, rule.ID,
+	); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+func (r *RecurringRuleRepository) Update(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	updates["updated_at"] = time.Now()
+
+	var setClauses []string
+	var args []interface{}
+	argPos := 1
+
+	for field, value := range updates {
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
+		args = append(args, value)
+		argPos++
+	}
+
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf(
+		"UPDATE recurring_rules SET %s WHERE id = $%d AND user_id = $%d",
+		strings.Join(setClauses, ", "),
+		argPos,
+		argPos+1,
+	)
+
+	result, err := auditedExec(r.db, r.audit, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("recurring rule not found")
+	}
+
+	return nil
+}
+
+func (r *RecurringRuleRepository) Delete(id, userID uuid.UUID) error {
+	query := "DELETE FROM recurring_rules WHERE id = $1 AND user_id = $2"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("recurring rule not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+type SettingsRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewSettingsRepository(db *sql.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+func (r *SettingsRepository) WithAudit(meta models.AuditMeta) *SettingsRepository {
+	return &SettingsRepository{db: r.db, audit: &meta}
+}
+
+// Get returns the user's settings, or the defaults when none were saved yet.
+func (r *SettingsRepository) Get(userID uuid.UUID) (*models.UserSettings, error) {
+	query := `
+		SELECT user_id, base_currency, created_at, updated_at
+		FROM user_settings
+		WHERE user_id = $1
+	`
+
+	settings := &models.UserSettings{}
+	err := r.db.QueryRow(query, userID).Scan(
+		&settings.UserID,
+		&settings.BaseCurrency,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return &models.UserSettings{
+			UserID:       userID,
+			BaseCurrency: models.DefaultCurrency,
+		}, nil
+	}
+
+	return settings, err
+}
+
+func (r *SettingsRepository) Upsert(settings *models.UserSettings) error {
+	query := `
+		INSERT INTO user_settings (user_id, base_currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET base_currency = EXCLUDED.base_currency, updated_at = EXCLUDED.updated_at
+		RETURNING created_at, updated_at
+	`
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			settings.UserID,
+			settings.BaseCurrency,
+			time.Now(),
+		).Scan(&settings.CreatedAt, &settings.UpdatedAt)
+	})
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+type TagRepository struct {
+	db    *sql.DB
+	audit *models.AuditMeta
+}
+
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) WithAudit(meta models.AuditMeta) *TagRepository {
+	return &TagRepository{db: r.db, audit: &meta}
+}
+
+func (r *TagRepository) Create(tag *models.Tag) error {
+	query := `
+		INSERT INTO tags (id, user_id, name, color, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	tag.ID = uuid.New()
+	tag.CreatedAt = time.Now()
+
+	return auditedWrite(r.db, r.audit, func(q queryer) error {
+		return q.QueryRow(
+			query,
+			tag.ID,
+			tag.UserID,
+			tag.Name,
+			tag.Color,
+			tag.CreatedAt,
+		).Scan(&tag.ID, &tag.CreatedAt)
+	})
+}
+
+func (r *TagRepository) GetByID(id, userID uuid.UUID) (*models.Tag, error) {
+	query := `
+		SELECT id, user_id, name, color, created_at
+		FROM tags
+		WHERE id = $1 AND user_id = $2
+	`
+
+	tag := &models.Tag{}
+	err := r.db.QueryRow(query, id, userID).Scan(
+		&tag.ID,
+		&tag.UserID,
+		&tag.Name,
+		&tag.Color,
+		&tag.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tag not found")
+	}
+
+	return tag, err
+}
+
+func (r *TagRepository) GetAll(userID uuid.UUID) ([]models.Tag, error) {
+	query := `
+		SELECT id, user_id, name, color, created_at
+		FROM tags
+		WHERE user_id = $1
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(
+			&tag.ID,
+			&tag.UserID,
+			&tag.Name,
+			&tag.Color,
+			&tag.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (r *TagRepository) Update(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	var setClauses []string
+	var args []interface{}
+	argPos := 1
+
+	for field, value := range updates {
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
+		args = append(args, value)
+		argPos++
+	}
+
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf(
+		"UPDATE tags SET %s WHERE id = $%d AND user_id = $%d",
+		strings.Join(setClauses, ", "),
+		argPos,
+		argPos+1,
+	)
+
+	result, err := auditedExec(r.db, r.audit, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("tag not found")
+	}
+
+	return nil
+}
+
+func (r *TagRepository) Delete(id, userID uuid.UUID) error {
+	query := "DELETE FROM tags WHERE id = $1 AND user_id = $2"
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("tag not found")
+	}
+
+	return nil
+}
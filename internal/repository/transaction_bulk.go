@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// runBulk calls fn for items 0..n-1 inside a single DB transaction, each in
+// its own savepoint so a failing item does not abort the others. It returns
+// the error of every item; the transaction is committed only when all of
+// them succeeded.
+func (r *TransactionRepository) runBulk(n int, fn func(tx *sql.Tx, i int) error) ([]error, error) {
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	errs := make([]error, n)
+	failed := false
+	for i := 0; i < n; i++ {
+		if _, err := tx.Exec("SAVEPOINT bulk_item"); err != nil {
+			return nil, err
+		}
+
+		if errs[i] = fn(tx, i); errs[i] != nil {
+			failed = true
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT bulk_item"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if _, err := tx.Exec("RELEASE SAVEPOINT bulk_item"); err != nil {
+			return nil, err
+		}
+	}
+
+	if failed {
+		return errs, nil
+	}
+
+	return errs, tx.Commit()
+}
+
+// MatchingIDs returns the IDs of up to limit transactions matching the
+// filters, oldest first.
+func (r *TransactionRepository) MatchingIDs(userID uuid.UUID, filters models.TransactionFilters, limit int) ([]uuid.UUID, error) {
+	whereClause, args := transactionFilterClause(userID, filters)
+
+	query := fmt.Sprintf(
+		"SELECT t.id FROM transactions t WHERE %s ORDER BY t.date ASC, t.created_at ASC, t.id ASC LIMIT $%d::int",
+		whereClause,
+		len(args)+1,
+	)
+
+	rows, err := r.db.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// ExpenseMonths returns the first day of each month in which one of the
+// transactions is an expense, to check the budget alerts after a change.
+func (r *TransactionRepository) ExpenseMonths(userID uuid.UUID, ids []uuid.UUID) ([]time.Time, error) {
+	query := `
+		SELECT DISTINCT DATE_TRUNC('month', date)::date
+		FROM transactions
+		WHERE user_id = $1 AND id = ANY($2::uuid[]) AND type = 'expense' AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.Query(query, userID, uuidArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var months []time.Time
+	for rows.Next() {
+		var month time.Time
+		if err := rows.Scan(&month); err != nil {
+			return nil, err
+		}
+		months = append(months, month)
+	}
+
+	return months, rows.Err()
+}
+
+// BulkCreate inserts the transactions, with their split lines and tags, all
+// or nothing.
+func (r *TransactionRepository) BulkCreate(transactions []models.Transaction) ([]error, error) {
+	return r.runBulk(len(transactions), func(tx *sql.Tx, i int) error {
+		transaction := &transactions[i]
+		if err := insertTransaction(tx, transaction); err != nil {
+			return err
+		}
+
+		if err := insertSplits(tx, transaction.ID, transaction.Splits); err != nil {
+			return err
+		}
+
+		tagIDs := make([]uuid.UUID, len(transaction.Tags))
+		for j, tag := range transaction.Tags {
+			tagIDs[j] = tag.ID
+		}
+		return addTags(tx, transaction.ID, transaction.UserID, tagIDs)
+	})
+}
+
+// BulkUpdate applies the same changes to every listed transaction, all or
+// nothing. Transfer legs are refused, as in single updates.
+func (r *TransactionRepository) BulkUpdate(userID uuid.UUID, ids []uuid.UUID, changes models.BulkUpdateTransactionsRequest) ([]error, error) {
+	setClauses := []string{"updated_at = $3"}
+	args := []interface{}{nil, userID, time.Now()}
+
+	if changes.CategoryID != nil {
+		args = append(args, *changes.CategoryID)
+		setClauses = append(setClauses, fmt.Sprintf("category_id = $%d", len(args)))
+	}
+	if changes.Type != "" {
+		args = append(args, changes.Type)
+		setClauses = append(setClauses, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if changes.DateShiftDays != 0 {
+		args = append(args, changes.DateShiftDays)
+		setClauses = append(setClauses, fmt.Sprintf("date = date + $%d::int * INTERVAL '1 day'", len(args)))
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE transactions SET %s WHERE id = $1 AND user_id = $2",
+		strings.Join(setClauses, ", "),
+	)
+
+	return r.runBulk(len(ids), func(tx *sql.Tx, i int) error {
+		id := ids[i]
+
+		var transferID *uuid.UUID
+		err := tx.QueryRow(
+			"SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE",
+			id, userID,
+		).Scan(&transferID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("transaction not found")
+		}
+		if err != nil {
+			return err
+		}
+		if transferID != nil {
+			return fmt.Errorf("transfer legs cannot be edited individually")
+		}
+
+		itemArgs := append([]interface{}{id}, args[1:]...)
+		if _, err := tx.Exec(query, itemArgs...); err != nil {
+			return err
+		}
+
+		if changes.TagIDs != nil {
+			if err := setTags(tx, id, userID, changes.TagIDs); err != nil {
+				return err
+			}
+		}
+		if err := addTags(tx, id, userID, changes.AddTagIDs); err != nil {
+			return err
+		}
+		if len(changes.RemoveTagIDs) > 0 {
+			if _, err := tx.Exec(
+				"DELETE FROM transaction_tags WHERE transaction_id = $1 AND tag_id = ANY($2::uuid[])",
+				id, uuidArray(changes.RemoveTagIDs),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// BulkDelete moves the listed transactions to the trash, all or nothing.
+// Deleting a transfer leg deletes the whole transfer, as in single deletes.
+func (r *TransactionRepository) BulkDelete(userID uuid.UUID, ids []uuid.UUID) ([]error, error) {
+	deleted := make(map[uuid.UUID]bool)
+	now := time.Now()
+
+	return r.runBulk(len(ids), func(tx *sql.Tx, i int) error {
+		// The other leg of a transfer listed earlier is already gone.
+		if deleted[ids[i]] {
+			return nil
+		}
+
+		rows, err := tx.Query(`
+			UPDATE transactions SET deleted_at = $3
+			WHERE user_id = $2 AND deleted_at IS NULL AND (
+				id = $1 OR transfer_id = (
+					SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+				)
+			)
+			RETURNING id
+		`, ids[i], userID, now)
+		if err != nil {
+			return err
+		}
+
+		var removed []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			removed = append(removed, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(removed) == 0 {
+			return fmt.Errorf("transaction not found")
+		}
+		for _, id := range removed {
+			deleted[id] = true
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor was not produced by
+// the same sort as the current request, or its values were tampered with.
+var ErrInvalidCursor = errors.New("cursor does not match the requested sort")
+
+// transactionSortColumn maps a sort field to its SQL expression over the "t"
+// and "c" aliases of transactionSelect, the cast of values compared against
+// it and how to render that value from a transaction.
+type transactionSortColumn struct {
+	expr  string
+	cast  string
+	value func(*models.Transaction) string
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// transactionSortColumns holds every SQL expression a sort can produce; sort
+// fields outside it never reach a query. "rank" is only used when searching,
+// where the "q" alias holds the query.
+var transactionSortColumns = map[string]transactionSortColumn{
+	"date":   {"t.date::date", "date", func(t *models.Transaction) string { return formatTime(t.Date) }},
+	"amount": {"t.amount", "numeric", func(t *models.Transaction) string { return t.Amount.String() }},
+	"category": {"COALESCE(c.name, '')", "text", func(t *models.Transaction) string {
+		if t.Category == nil {
+			return ""
+		}
+		return t.Category.Name
+	}},
+	"created_at": {"t.created_at", "timestamptz", func(t *models.Transaction) string { return formatTime(t.CreatedAt) }},
+	"updated_at": {"t.updated_at", "timestamptz", func(t *models.Transaction) string { return formatTime(t.UpdatedAt) }},
+	"id":         {"t.id", "uuid", func(t *models.Transaction) string { return t.ID.String() }},
+	"rank": {"ts_rank(t.search_vector, q.query)", "real", func(t *models.Transaction) string {
+		if t.Rank == nil {
+			return "0"
+		}
+		return strconv.FormatFloat(*t.Rank, 'g', -1, 64)
+	}},
+}
+
+// cursorValueParsers check that a cursor value can be cast to the column type
+// it is compared against; casts missing here accept any text.
+var cursorValueParsers = map[string]func(string) error{
+	"date":        func(v string) error { _, err := time.Parse(time.RFC3339Nano, v); return err },
+	"timestamptz": func(v string) error { _, err := time.Parse(time.RFC3339Nano, v); return err },
+	"numeric":     func(v string) error { _, err := models.ParseMoney(v); return err },
+	"uuid":        func(v string) error { _, err := uuid.Parse(v); return err },
+	"real":        func(v string) error { _, err := strconv.ParseFloat(v, 32); return err },
+}
+
+// validCursor reports whether a cursor was taken from the given order and
+// holds one well-formed value per key.
+func validCursor(keys []models.SortKey, cursor *models.Cursor) bool {
+	if cursor.Order != orderSignature(keys) || len(cursor.Values) != len(keys) {
+		return false
+	}
+	for i, key := range keys {
+		if parse := cursorValueParsers[transactionSortColumns[key.Field].cast]; parse != nil && parse(cursor.Values[i]) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Default orders of the listing (newest first) and of exports (oldest first).
+var (
+	transactionListOrder = []models.SortKey{
+		{Field: "date", Desc: true},
+		{Field: "created_at", Desc: true},
+	}
+	transactionExportOrder = []models.SortKey{
+		{Field: "date"},
+		{Field: "created_at"},
+	}
+)
+
+// transactionOrder returns the keys of the requested sort, or the defaults
+// when none was given, always ending with the ID so the order is total.
+func transactionOrder(sort models.TransactionSort, defaults []models.SortKey) []models.SortKey {
+	keys := sort.Keys()
+	if len(keys) == 0 {
+		keys = defaults
+	}
+
+	return append(keys[:len(keys):len(keys)], models.SortKey{Field: "id"})
+}
+
+// reverseOrder flips the direction of every key.
+func reverseOrder(keys []models.SortKey) []models.SortKey {
+	reversed := make([]models.SortKey, len(keys))
+	for i, key := range keys {
+		reversed[i] = models.SortKey{Field: key.Field, Desc: !key.Desc}
+	}
+	return reversed
+}
+
+// orderSignature identifies an order inside cursors, e.g. "-amount,date,id".
+func orderSignature(keys []models.SortKey) string {
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = key.Field
+		if key.Desc {
+			fields[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// orderByClause renders the keys as an ORDER BY list.
+func orderByClause(keys []models.SortKey) string {
+	terms := make([]string, len(keys))
+	for i, key := range keys {
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
+		}
+		terms[i] = transactionSortColumns[key.Field].expr + " " + direction
+	}
+	return strings.Join(terms, ", ")
+}
+
+// sortValues returns the values of the sort keys of a transaction.
+func sortValues(keys []models.SortKey, transaction *models.Transaction) []string {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = transactionSortColumns[key.Field].value(transaction)
+	}
+	return values
+}
+
+// transactionCursor returns the cursor of a transaction in the given order.
+func transactionCursor(keys []models.SortKey, transaction *models.Transaction) string {
+	return models.Cursor{Order: orderSignature(keys), Values: sortValues(keys, transaction)}.Encode()
+}
+
+// afterClause builds the condition selecting the rows that come after the
+// given sort values in the order of keys, with its arguments numbered from
+// argPos: (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., with < for descending
+// keys.
+func afterClause(keys []models.SortKey, values []string, argPos int) (string, []interface{}) {
+	var alternatives []string
+	var args []interface{}
+	var equal []string
+
+	for i, key := range keys {
+		column := transactionSortColumns[key.Field]
+		param := fmt.Sprintf("$%d::%s", argPos, column.cast)
+		args = append(args, values[i])
+		argPos++
+
+		operator := ">"
+		if key.Desc {
+			operator = "<"
+		}
+
+		terms := append(equal[:len(equal):len(equal)], fmt.Sprintf("%s %s %s", column.expr, operator, param))
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+		equal = append(equal, fmt.Sprintf("%s = %s", column.expr, param))
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
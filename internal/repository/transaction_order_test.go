@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestValidCursor(t *testing.T) {
+	rank := 0.25
+	transaction := &models.Transaction{
+		ID:        uuid.MustParse("5b1f0c1e-8a4e-4c57-9d0b-2f1d6c3a7e90"),
+		Amount:    15050,
+		Date:      time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		CreatedAt: time.Date(2024, 1, 5, 12, 30, 0, 123456000, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC),
+		Category:  &models.Category{Name: "Mercado"},
+		Rank:      &rank,
+	}
+
+	order := transactionOrder("-amount,category", transactionListOrder)
+	search := append([]models.SortKey{{Field: "rank", Desc: true}}, transactionOrder("", transactionListOrder)...)
+	all := transactionOrder("date,amount,category,created_at,updated_at", nil)
+
+	decode := func(token string) *models.Cursor {
+		cursor, err := models.DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("DecodeCursor: %v", err)
+		}
+		return cursor
+	}
+	tampered := func(keys []models.SortKey, i int, value string) *models.Cursor {
+		cursor := decode(transactionCursor(keys, transaction))
+		cursor.Values[i] = value
+		return cursor
+	}
+
+	tests := []struct {
+		name   string
+		keys   []models.SortKey
+		cursor *models.Cursor
+		want   bool
+	}{
+		{name: "round trip", keys: order, cursor: decode(transactionCursor(order, transaction)), want: true},
+		{name: "round trip when searching", keys: search, cursor: decode(transactionCursor(search, transaction)), want: true},
+		{name: "round trip of every field", keys: all, cursor: decode(transactionCursor(all, transaction)), want: true},
+		{name: "other order", keys: transactionOrder("", transactionListOrder), cursor: decode(transactionCursor(order, transaction))},
+		{name: "other direction", keys: reverseOrder(order), cursor: decode(transactionCursor(order, transaction))},
+		{name: "too few values", keys: order, cursor: &models.Cursor{Order: orderSignature(order), Values: []string{"150.50"}}},
+		{name: "bad amount", keys: order, cursor: tampered(order, 0, "1; DROP TABLE transactions")},
+		{name: "bad id", keys: order, cursor: tampered(order, 2, "42")},
+		{name: "bad date", keys: all, cursor: tampered(all, 0, "2024-13-01")},
+		{name: "bad timestamp", keys: all, cursor: tampered(all, 3, "yesterday")},
+		{name: "bad rank", keys: search, cursor: tampered(search, 0, "high")},
+		{name: "any category name", keys: order, cursor: tampered(order, 1, "'; --"), want: true},
+	}
+
+	for _, tt := range tests {
+		if got := validCursor(tt.keys, tt.cursor); got != tt.want {
+			t.Errorf("%s: validCursor(%s, %+v) = %v, want %v", tt.name, orderSignature(tt.keys), *tt.cursor, got, tt.want)
+		}
+	}
+}
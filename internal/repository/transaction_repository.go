@@ -3,354 +3,1382 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"html"
 	"strings"
 	"time"
 
 	"github.com/Gildaciolopes/fintrack-api/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type TransactionRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	audit *models.AuditMeta
 }
 
 func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (r *TransactionRepository) Create(transaction *models.Transaction) error {
-	query := `
-		INSERT INTO transactions (id, user_id, category_id, type, amount, description, date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, created_at, updated_at
-	`
-
-	transaction.ID = uuid.New()
-	transaction.CreatedAt = time.Now()
-	transaction.UpdatedAt = time.Now()
-
-	return r.db.QueryRow(
-		query,
-		transaction.ID,
-		transaction.UserID,
-		transaction.CategoryID,
-		transaction.Type,
-		transaction.Amount,
-		transaction.Description,
-		transaction.Date,
-		transaction.CreatedAt,
-		transaction.UpdatedAt,
-	).Scan(&transaction.ID, &transaction.CreatedAt, &transaction.UpdatedAt)
+func (r *TransactionRepository) WithAudit(meta models.AuditMeta) *TransactionRepository {
+	return &TransactionRepository{db: r.db, audit: &meta}
 }
 
-func (r *TransactionRepository) GetByID(id, userID uuid.UUID) (*models.Transaction, error) {
-	query := `
-		SELECT 
-			t.id, t.user_id, t.category_id, t.type, t.amount, t.description, t.date, 
+// transactionColumns are the columns read by scanTransaction.
+const transactionColumns = `
+			t.id, t.user_id, t.category_id, t.account_id, t.payee_id, t.transfer_id, t.recurring_rule_id, t.type, t.amount, t.currency, t.description, t.external_id, t.date, 
 			t.created_at, t.updated_at,
-			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
+			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at`
+
+// transactionFrom joins the (optional) category of the transactions. A
+// category in the trash is left out, as if the transaction had none.
+const transactionFrom = `
 		FROM transactions t
-		LEFT JOIN categories c ON t.category_id = c.id
-		WHERE t.id = $1 AND t.user_id = $2
-	`
+		LEFT JOIN categories c ON t.category_id = c.id AND c.deleted_at IS NULL
+`
+
+// transactionSelect is the query prefix shared by every query returning
+// transactions together with their (optional) category.
+const transactionSelect = `
+		SELECT ` + transactionColumns + transactionFrom
+
+// searchConfig is the text search configuration built by the search
+// migration: Portuguese stemming with accents folded.
+const searchConfig = "fintrack_pt"
+
+// Highlighted terms are wrapped in these control characters by ts_headline and
+// turned into <mark> tags once the rest of the snippet is HTML-escaped.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// currencyOrDefault builds the SQL expression used when inserting a
+// transaction currency: the given value, else the account currency, else the
+// user base currency, else models.DefaultCurrency.
+func currencyOrDefault(currencyArg, accountArg, userArg string) string {
+	return fmt.Sprintf(`COALESCE(
+			NULLIF(%[1]s::text, ''),
+			(SELECT currency FROM accounts WHERE id = %[2]s::uuid AND user_id = %[3]s::uuid),
+			(SELECT base_currency FROM user_settings WHERE user_id = %[3]s::uuid),
+			'%[4]s'
+		)`, currencyArg, accountArg, userArg, models.DefaultCurrency)
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// nullableCategory receives the columns of a LEFT JOINed category.
+type nullableCategory struct {
+	ID, UserID              sql.NullString
+	Name, Type, Color, Icon sql.NullString
+	CreatedAt               sql.NullTime
+}
+
+func (n *nullableCategory) dest() []interface{} {
+	return []interface{}{&n.ID, &n.UserID, &n.Name, &n.Type, &n.Color, &n.Icon, &n.CreatedAt}
+}
+
+func (n *nullableCategory) category() *models.Category {
+	if !n.ID.Valid {
+		return nil
+	}
+
+	categoryUUID, _ := uuid.Parse(n.ID.String)
+	categoryUserUUID, _ := uuid.Parse(n.UserID.String)
+	return &models.Category{
+		ID:        categoryUUID,
+		UserID:    categoryUserUUID,
+		Name:      n.Name.String,
+		Type:      n.Type.String,
+		Color:     n.Color.String,
+		Icon:      n.Icon.String,
+		CreatedAt: n.CreatedAt.Time,
+	}
+}
 
+// scanTransaction reads the transactionColumns of a row, followed by the
+// extra destinations, if any.
+func scanTransaction(row rowScanner, extra ...interface{}) (*models.Transaction, error) {
 	transaction := &models.Transaction{}
-	var category models.Category
-	var categoryID, categoryUserID sql.NullString
-	var categoryName, categoryType, categoryColor, categoryIcon sql.NullString
-	var categoryCreatedAt sql.NullTime
+	var category nullableCategory
 
-	err := r.db.QueryRow(query, id, userID).Scan(
+	dest := []interface{}{
 		&transaction.ID,
 		&transaction.UserID,
 		&transaction.CategoryID,
+		&transaction.AccountID,
+		&transaction.PayeeID,
+		&transaction.TransferID,
+		&transaction.RecurringRuleID,
 		&transaction.Type,
 		&transaction.Amount,
+		&transaction.Currency,
 		&transaction.Description,
+		&transaction.ExternalID,
 		&transaction.Date,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
-		&categoryID,
-		&categoryUserID,
-		&categoryName,
-		&categoryType,
-		&categoryColor,
-		&categoryIcon,
-		&categoryCreatedAt,
-	)
+	}
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("transaction not found")
+	dest = append(dest, category.dest()...)
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	transaction.Category = category.category()
+	return transaction, nil
+}
+
+// Create inserts the transaction together with its split lines and tags, if
+// any. Tags are taken from the IDs in transaction.Tags and reloaded in full.
+func (r *TransactionRepository) Create(transaction *models.Transaction) error {
+	if len(transaction.Splits) == 0 && len(transaction.Tags) == 0 {
+		return auditedWrite(r.db, r.audit, func(q queryer) error {
+			return insertTransaction(q, transaction)
+		})
 	}
+
+	tx, err := auditedBegin(r.db, r.audit)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer tx.Rollback()
 
-	if categoryID.Valid {
-		categoryUUID, _ := uuid.Parse(categoryID.String)
-		categoryUserUUID, _ := uuid.Parse(categoryUserID.String)
-		category.ID = categoryUUID
-		category.UserID = categoryUserUUID
-		category.Name = categoryName.String
-		category.Type = categoryType.String
-		category.Color = categoryColor.String
-		category.Icon = categoryIcon.String
-		category.CreatedAt = categoryCreatedAt.Time
-		transaction.Category = &category
+	if err := insertTransaction(tx, transaction); err != nil {
+		return err
 	}
 
-	return transaction, nil
+	if err := insertSplits(tx, transaction.ID, transaction.Splits); err != nil {
+		return err
+	}
+
+	if len(transaction.Tags) > 0 {
+		tagIDs := make([]uuid.UUID, len(transaction.Tags))
+		for i, tag := range transaction.Tags {
+			tagIDs[i] = tag.ID
+		}
+
+		if err := setTags(tx, transaction.ID, transaction.UserID, tagIDs); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	transaction.Tags = nil
+	transactions := []models.Transaction{*transaction}
+	if err := r.attachTags(transactions); err != nil {
+		return err
+	}
+	*transaction = transactions[0]
+
+	return nil
 }
 
-func (r *TransactionRepository) GetAll(userID uuid.UUID, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
-	if filters.Page == 0 {
-		filters.Page = 1
+// CreateBatch inserts all the transactions, with their tags (by ID), in a
+// single DB transaction: either every one is stored or none is.
+func (r *TransactionRepository) CreateBatch(transactions []models.Transaction) error {
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return err
 	}
-	if filters.Limit == 0 {
-		filters.Limit = 20
+	defer tx.Rollback()
+
+	for i := range transactions {
+		if err := insertTransaction(tx, &transactions[i]); err != nil {
+			return fmt.Errorf("transaction %d: %w", i+1, err)
+		}
+
+		tagIDs := make([]uuid.UUID, len(transactions[i].Tags))
+		for j, tag := range transactions[i].Tags {
+			tagIDs[j] = tag.ID
+		}
+		if err := addTags(tx, transactions[i].ID, transactions[i].UserID, tagIDs); err != nil {
+			return fmt.Errorf("transaction %d: %w", i+1, err)
+		}
 	}
 
-	whereClause := "t.user_id = $1::uuid"
-	args := []interface{}{userID}
-	argPos := 2
+	return tx.Commit()
+}
 
-	if filters.Type != "" {
-		whereClause += fmt.Sprintf(" AND t.type = $%d", argPos)
-		args = append(args, filters.Type)
-		argPos++
+// ApplyRuleChanges saves the changes computed by the categorization rules in
+// a single DB transaction.
+func (r *TransactionRepository) ApplyRuleChanges(userID uuid.UUID, changes []models.RuleChange) error {
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	if filters.CategoryID != nil {
-		whereClause += fmt.Sprintf(" AND t.category_id = $%d::uuid", argPos)
-		args = append(args, *filters.CategoryID)
-		argPos++
+	query := `
+		UPDATE transactions
+		SET category_id = COALESCE($3, category_id),
+			description = COALESCE($4, description),
+			updated_at = $5
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	for _, change := range changes {
+		if change.CategoryID != nil || change.Description != nil {
+			if _, err := tx.Exec(query, change.TransactionID, userID, change.CategoryID, change.Description, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		if err := addTags(tx, change.TransactionID, userID, change.AddTagIDs); err != nil {
+			return err
+		}
 	}
 
-	if filters.StartDate != nil {
-		whereClause += fmt.Sprintf(" AND t.date >= $%d::date", argPos)
-		args = append(args, *filters.StartDate)
-		argPos++
+	return tx.Commit()
+}
+
+// SetPayees links transactions to payees (transaction ID to payee ID) in a
+// single DB transaction.
+func (r *TransactionRepository) SetPayees(userID uuid.UUID, payees map[uuid.UUID]uuid.UUID) error {
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	if filters.EndDate != nil {
-		whereClause += fmt.Sprintf(" AND t.date <= $%d::date", argPos)
-		args = append(args, *filters.EndDate)
-		argPos++
+	query := "UPDATE transactions SET payee_id = $3, updated_at = $4 WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+
+	for transactionID, payeeID := range payees {
+		if _, err := tx.Exec(query, transactionID, userID, payeeID, time.Now()); err != nil {
+			return err
+		}
 	}
 
-	if filters.MinAmount != nil {
-		whereClause += fmt.Sprintf(" AND t.amount >= $%d", argPos)
-		args = append(args, *filters.MinAmount)
-		argPos++
+	return tx.Commit()
+}
+
+// ExistingExternalIDs returns which of the given external IDs the user already
+// has transactions for. Transactions in the trash count too: importing them
+// again would clash with the restore.
+func (r *TransactionRepository) ExistingExternalIDs(userID uuid.UUID, externalIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(externalIDs) == 0 {
+		return existing, nil
 	}
 
-	if filters.MaxAmount != nil {
-		whereClause += fmt.Sprintf(" AND t.amount <= $%d", argPos)
-		args = append(args, *filters.MaxAmount)
-		argPos++
+	rows, err := r.db.Query(
+		"SELECT external_id FROM transactions WHERE user_id = $1 AND external_id = ANY($2)",
+		userID,
+		pq.Array(externalIDs),
+	)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM transactions t WHERE %s", whereClause)
-	var totalCount int64
-	if err := r.db.QueryRow(countQuery, args...).Scan(&totalCount); err != nil {
-		return nil, 0, err
+	for rows.Next() {
+		var externalID string
+		if err := rows.Scan(&externalID); err != nil {
+			return nil, err
+		}
+		existing[externalID] = true
 	}
 
-	offset := (filters.Page - 1) * filters.Limit
-	query := fmt.Sprintf(`
-		SELECT 
-			t.id, t.user_id, t.category_id, t.type, t.amount, t.description, t.date, 
-			t.created_at, t.updated_at,
-			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
+	return existing, rows.Err()
+}
+
+// TrainingSet returns the user's most recent categorized transactions with a
+// description, leaving out transfers and split transactions, to train the
+// category classifier. Only the description, type, amount and category are
+// loaded.
+func (r *TransactionRepository) TrainingSet(userID uuid.UUID, limit int) ([]models.Transaction, error) {
+	query := `
+		SELECT t.id, t.category_id, t.type, t.amount, t.description
 		FROM transactions t
-		LEFT JOIN categories c ON t.category_id = c.id
-		WHERE %s
+		WHERE t.user_id = $1
+			AND t.deleted_at IS NULL
+			AND t.category_id IS NOT NULL
+			AND t.description IS NOT NULL
+			AND t.transfer_id IS NULL
+			AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
 		ORDER BY t.date DESC, t.created_at DESC
-		LIMIT $%d::int OFFSET $%d::int
-	`, whereClause, argPos, argPos+1)
-
-	args = append(args, filters.Limit, offset)
+		LIMIT $2
+	`
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(query, userID, limit)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
 	var transactions []models.Transaction
 	for rows.Next() {
-		var transaction models.Transaction
-		var category models.Category
-		var categoryID, categoryUserID sql.NullString
-		var categoryName, categoryType, categoryColor, categoryIcon sql.NullString
-		var categoryCreatedAt sql.NullTime
-
+		transaction := models.Transaction{UserID: userID}
 		if err := rows.Scan(
 			&transaction.ID,
-			&transaction.UserID,
 			&transaction.CategoryID,
 			&transaction.Type,
 			&transaction.Amount,
 			&transaction.Description,
-			&transaction.Date,
-			&transaction.CreatedAt,
-			&transaction.UpdatedAt,
-			&categoryID,
-			&categoryUserID,
-			&categoryName,
-			&categoryType,
-			&categoryColor,
-			&categoryIcon,
-			&categoryCreatedAt,
 		); err != nil {
-			return nil, 0, err
-		}
-
-		if categoryID.Valid {
-			categoryUUID, _ := uuid.Parse(categoryID.String)
-			categoryUserUUID, _ := uuid.Parse(categoryUserID.String)
-			category.ID = categoryUUID
-			category.UserID = categoryUserUUID
-			category.Name = categoryName.String
-			category.Type = categoryType.String
-			category.Color = categoryColor.String
-			category.Icon = categoryIcon.String
-			category.CreatedAt = categoryCreatedAt.Time
-			transaction.Category = &category
+			return nil, err
 		}
-
 		transactions = append(transactions, transaction)
 	}
 
-	return transactions, totalCount, rows.Err()
+	return transactions, rows.Err()
 }
 
-func (r *TransactionRepository) Update(id, userID uuid.UUID, updates map[string]interface{}) error {
-	if len(updates) == 0 {
-		return fmt.Errorf("no fields to update")
+// DuplicateCandidates returns the user's transactions, other than transfers,
+// dated between start and end with one of the given amounts: the ones a new
+// transaction could duplicate.
+func (r *TransactionRepository) DuplicateCandidates(userID uuid.UUID, start, end time.Time, amounts []models.Money) ([]models.Transaction, error) {
+	if len(amounts) == 0 {
+		return nil, nil
 	}
 
-	updates["updated_at"] = time.Now()
+	values := make([]string, len(amounts))
+	for i, amount := range amounts {
+		values[i] = amount.String()
+	}
 
-	var setClauses []string
-	var args []interface{}
-	argPos := 1
+	query := transactionSelect + `
+		WHERE t.user_id = $1
+			AND t.deleted_at IS NULL
+			AND t.transfer_id IS NULL
+			AND t.date BETWEEN $2::date AND $3::date
+			AND t.amount = ANY($4::numeric[])
+		ORDER BY t.date ASC
+	`
 
-	for field, value := range updates {
-		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
-		args = append(args, value)
-		argPos++
+	rows, err := r.db.Query(query, userID, start, end, pq.Array(uniqueStrings(values)))
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	args = append(args, id, userID)
+	var transactions []models.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, *transaction)
+	}
 
-	query := fmt.Sprintf(
-		"UPDATE transactions SET %s WHERE id = $%d AND user_id = $%d",
-		strings.Join(setClauses, ", "),
-		argPos,
-		argPos+1,
-	)
+	return transactions, rows.Err()
+}
 
-	result, err := r.db.Exec(query, args...)
+// MergeDuplicates folds the duplicates into the kept transaction inside one DB
+// transaction: the kept one gets the tags and attachments of all of them and,
+// where it has none, the first category (unless it is split), description and
+// external ID found among them. The duplicates are then moved to the trash,
+// so a wrong merge can be undone, without their external ID.
+func (r *TransactionRepository) MergeDuplicates(userID, keepID uuid.UUID, duplicateIDs []uuid.UUID) error {
+	tx, err := auditedBegin(r.db, r.audit)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+	ids := uuidArray(duplicateIDs)
+
+	if _, err := tx.Exec(`
+		INSERT INTO transaction_tags (transaction_id, tag_id)
+		SELECT $1, tt.tag_id
+		FROM transaction_tags tt
+		JOIN transactions t ON t.id = tt.transaction_id
+		WHERE t.user_id = $2 AND t.id = ANY($3::uuid[]) AND t.deleted_at IS NULL
+		ON CONFLICT DO NOTHING
+	`, keepID, userID, ids); err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("transaction not found")
+	var categoryID *uuid.UUID
+	var description, externalID *string
+	rows, err := tx.Query(`
+		SELECT category_id, description, external_id
+		FROM transactions
+		WHERE user_id = $1 AND id = ANY($2::uuid[]) AND deleted_at IS NULL
+		ORDER BY date ASC, created_at ASC
+	`, userID, ids)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var rowCategoryID *uuid.UUID
+		var rowDescription, rowExternalID *string
+		if err := rows.Scan(&rowCategoryID, &rowDescription, &rowExternalID); err != nil {
+			rows.Close()
+			return err
+		}
+		if categoryID == nil {
+			categoryID = rowCategoryID
+		}
+		if description == nil {
+			description = rowDescription
+		}
+		if externalID == nil {
+			externalID = rowExternalID
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	return nil
-}
+	if _, err := tx.Exec(`
+		UPDATE attachments SET transaction_id = $1
+		WHERE user_id = $2 AND transaction_id = ANY($3::uuid[]) AND transaction_id <> $1
+	`, keepID, userID, ids); err != nil {
+		return err
+	}
 
-func (r *TransactionRepository) Delete(id, userID uuid.UUID) error {
-	query := "DELETE FROM transactions WHERE id = $1 AND user_id = $2"
+	// Trashed first, clearing the external ID so it can move without breaking
+	// its uniqueness.
+	if _, err := tx.Exec(`
+		UPDATE transactions SET deleted_at = $4, external_id = NULL
+		WHERE user_id = $1 AND id = ANY($2::uuid[]) AND id <> $3 AND deleted_at IS NULL
+	`, userID, ids, keepID, time.Now()); err != nil {
+		return err
+	}
 
-	result, err := r.db.Exec(query, id, userID)
+	result, err := tx.Exec(`
+		UPDATE transactions
+		SET category_id = CASE
+				WHEN EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id) THEN category_id
+				ELSE COALESCE(category_id, $3)
+			END,
+			description = COALESCE(description, $4),
+			external_id = COALESCE(external_id, $5),
+			updated_at = $6
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, keepID, userID, categoryID, description, externalID, time.Now())
 	if err != nil {
 		return err
 	}
 
-	rows, err := result.RowsAffected()
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
-
-	if rows == 0 {
+	if affected == 0 {
 		return fmt.Errorf("transaction not found")
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-func (r *TransactionRepository) GetRecentTransactions(userID uuid.UUID, limit int) ([]models.Transaction, error) {
+// insertTransaction writes a new transaction using either the connection pool
+// or an open DB transaction. An empty currency defaults to the account
+// currency, then to the user base currency.
+func insertTransaction(q queryer, transaction *models.Transaction) error {
 	query := `
-		SELECT 
-			t.id, t.user_id, t.category_id, t.type, t.amount, t.description, t.date, 
-			t.created_at, t.updated_at,
-			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
-		FROM transactions t
-		LEFT JOIN categories c ON t.category_id = c.id
-		WHERE t.user_id = $1
-		ORDER BY t.date DESC, t.created_at DESC
-		LIMIT $2
+		INSERT INTO transactions (id, user_id, category_id, account_id, payee_id, transfer_id, type, amount, currency, description, external_id, date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, ` + currencyOrDefault("$9", "$4", "$2") + `, $10, $11, $12, $13, $14)
+		RETURNING id, currency, created_at, updated_at
 	`
 
-	rows, err := r.db.Query(query, userID, limit)
+	transaction.ID = uuid.New()
+	transaction.CreatedAt = time.Now()
+	transaction.UpdatedAt = time.Now()
+
+	return q.QueryRow(
+		query,
+		transaction.ID,
+		transaction.UserID,
+		transaction.CategoryID,
+		transaction.AccountID,
+		transaction.PayeeID,
+		transaction.TransferID,
+		transaction.Type,
+		transaction.Amount,
+		transaction.Currency,
+		transaction.Description,
+		transaction.ExternalID,
+		transaction.Date,
+		transaction.CreatedAt,
+		transaction.UpdatedAt,
+	).Scan(&transaction.ID, &transaction.Currency, &transaction.CreatedAt, &transaction.UpdatedAt)
+}
+
+func (r *TransactionRepository) GetByID(id, userID uuid.UUID) (*models.Transaction, error) {
+	query := transactionSelect + `
+		WHERE t.id = $1 AND t.user_id = $2 AND t.deleted_at IS NULL
+	`
+
+	transaction, err := scanTransaction(r.db.QueryRow(query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("transaction not found")
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var transactions []models.Transaction
-	for rows.Next() {
-		var transaction models.Transaction
-		var category models.Category
-		var categoryID, categoryUserID sql.NullString
-		var categoryName, categoryType, categoryColor, categoryIcon sql.NullString
-		var categoryCreatedAt sql.NullTime
+	transactions := []models.Transaction{*transaction}
+	if err := r.attachSplits(transactions); err != nil {
+		return nil, err
+	}
+	if err := r.attachTags(transactions); err != nil {
+		return nil, err
+	}
 
-		if err := rows.Scan(
-			&transaction.ID,
-			&transaction.UserID,
-			&transaction.CategoryID,
-			&transaction.Type,
-			&transaction.Amount,
-			&transaction.Description,
-			&transaction.Date,
-			&transaction.CreatedAt,
-			&transaction.UpdatedAt,
-			&categoryID,
-			&categoryUserID,
-			&categoryName,
-			&categoryType,
-			&categoryColor,
-			&categoryIcon,
-			&categoryCreatedAt,
-		); err != nil {
-			return nil, err
-		}
+	return &transactions[0], nil
+}
 
-		if categoryID.Valid {
-			categoryUUID, _ := uuid.Parse(categoryID.String)
-			categoryUserUUID, _ := uuid.Parse(categoryUserID.String)
-			category.ID = categoryUUID
-			category.UserID = categoryUserUUID
-			category.Name = categoryName.String
-			category.Type = categoryType.String
-			category.Color = categoryColor.String
-			category.Icon = categoryIcon.String
-			category.CreatedAt = categoryCreatedAt.Time
-			transaction.Category = &category
+// transactionFilterClause builds the WHERE clause (over the "t" alias) and its
+// arguments for the filters shared by listing and exporting. Pagination
+// fields are ignored and transactions in the trash are always left out.
+func transactionFilterClause(userID uuid.UUID, filters models.TransactionFilters) (string, []interface{}) {
+	whereClause := "t.user_id = $1::uuid AND t.deleted_at IS NULL"
+	args := []interface{}{userID}
+	argPos := 2
+
+	if filters.Type != "" {
+		whereClause += fmt.Sprintf(" AND t.type = $%d", argPos)
+		args = append(args, filters.Type)
+		argPos++
+	}
+
+	if filters.CategoryID != nil {
+		whereClause += fmt.Sprintf(
+			" AND (t.category_id = $%[1]d::uuid OR EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id AND s.category_id = $%[1]d::uuid))",
+			argPos,
+		)
+		args = append(args, *filters.CategoryID)
+		argPos++
+	}
+
+	if filters.AccountID != nil {
+		whereClause += fmt.Sprintf(" AND t.account_id = $%d::uuid", argPos)
+		args = append(args, *filters.AccountID)
+		argPos++
+	}
+
+	if filters.PayeeID != nil {
+		whereClause += fmt.Sprintf(" AND t.payee_id = $%d::uuid", argPos)
+		args = append(args, *filters.PayeeID)
+		argPos++
+	}
+
+	if filters.StartDate != nil {
+		whereClause += fmt.Sprintf(" AND t.date >= $%d::date", argPos)
+		args = append(args, *filters.StartDate)
+		argPos++
+	}
+
+	if filters.EndDate != nil {
+		whereClause += fmt.Sprintf(" AND t.date <= $%d::date", argPos)
+		args = append(args, *filters.EndDate)
+		argPos++
+	}
+
+	if filters.MinAmount != nil {
+		whereClause += fmt.Sprintf(" AND t.amount >= $%d", argPos)
+		args = append(args, *filters.MinAmount)
+		argPos++
+	}
+
+	if filters.MaxAmount != nil {
+		whereClause += fmt.Sprintf(" AND t.amount <= $%d", argPos)
+		args = append(args, *filters.MaxAmount)
+		argPos++
+	}
+
+	if q := strings.TrimSpace(filters.Q); q != "" {
+		whereClause += fmt.Sprintf(" AND t.search_vector @@ websearch_to_tsquery('%s', $%d)", searchConfig, argPos)
+		args = append(args, q)
+		argPos++
+	}
+
+	if len(filters.Tags) > 0 {
+		tags := uniqueStrings(filters.Tags)
+		if filters.TagMatch == "all" {
+			whereClause += fmt.Sprintf(
+				" AND (SELECT COUNT(DISTINCT tt.tag_id) FROM transaction_tags tt WHERE tt.transaction_id = t.id AND tt.tag_id = ANY($%[1]d::uuid[])) = cardinality($%[1]d::uuid[])",
+				argPos,
+			)
+		} else {
+			whereClause += fmt.Sprintf(
+				" AND EXISTS (SELECT 1 FROM transaction_tags tt WHERE tt.transaction_id = t.id AND tt.tag_id = ANY($%d::uuid[]))",
+				argPos,
+			)
 		}
+		args = append(args, pq.Array(tags))
+		argPos++
+	}
 
-		transactions = append(transactions, transaction)
+	return whereClause, args
+}
+
+// GetAll returns a page of the transactions matching the filters. Pages are
+// reached from the filters.After or filters.Before cursor (keyset pagination)
+// or, without one, by page number. One extra row is fetched to know whether
+// another page follows.
+func (r *TransactionRepository) GetAll(userID uuid.UUID, filters models.TransactionFilters) (*models.TransactionPage, error) {
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 20
 	}
 
-	return transactions, rows.Err()
+	whereClause, args := transactionFilterClause(userID, filters)
+	page := &models.TransactionPage{}
+
+	if !filters.SkipCount {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM transactions t WHERE %s", whereClause)
+		var totalCount int64
+		if err := r.db.QueryRow(countQuery, args...).Scan(&totalCount); err != nil {
+			return nil, err
+		}
+		page.TotalCount = &totalCount
+	}
+
+	search := strings.TrimSpace(filters.Q)
+	order := transactionOrder(filters.Sort, transactionListOrder)
+	if search != "" && len(filters.Sort.Keys()) == 0 {
+		// Best matches first unless another sort was asked for.
+		order = append([]models.SortKey{{Field: "rank", Desc: true}}, order...)
+	}
+
+	// Going backwards the rows are read in reverse order, then flipped.
+	cursor, backward := filters.After, false
+	queryOrder := order
+	if filters.Before != nil {
+		cursor, backward = filters.Before, true
+		queryOrder = reverseOrder(order)
+	}
+
+	argPos := len(args) + 1
+	offset := 0
+	if cursor != nil {
+		if !validCursor(order, cursor) {
+			return nil, ErrInvalidCursor
+		}
+		after, afterArgs := afterClause(queryOrder, cursor.Values, argPos)
+		whereClause += " AND " + after
+		args = append(args, afterArgs...)
+		argPos += len(afterArgs)
+	} else {
+		offset = (filters.Page - 1) * filters.Limit
+	}
+
+	columns, from := transactionColumns, transactionFrom
+	if search != "" {
+		// Each match comes with a snippet of the matched text.
+		columns += fmt.Sprintf(`,
+			ts_rank(t.search_vector, q.query),
+			ts_headline('%[1]s',
+				array_to_string(array_remove(transaction_search_text(t.id, t.description, t.category_id, t.payee_id), ''), ' · '),
+				q.query, $%[2]d)
+		`, searchConfig, argPos)
+		from += fmt.Sprintf("CROSS JOIN websearch_to_tsquery('%s', $%d) AS q(query)\n", searchConfig, argPos+1)
+		args = append(args, fmt.Sprintf(
+			`StartSel="%s", StopSel="%s", MaxFragments=2, MaxWords=15, MinWords=5`,
+			highlightStart, highlightStop,
+		), search)
+		argPos += 2
+	}
+
+	query := "SELECT" + columns + from + fmt.Sprintf(`
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d::int OFFSET $%d::int
+	`, whereClause, orderByClause(queryOrder), argPos, argPos+1)
+
+	args = append(args, filters.Limit+1, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		var rank float64
+		var headline string
+		var extra []interface{}
+		if search != "" {
+			extra = []interface{}{&rank, &headline}
+		}
+
+		transaction, err := scanTransaction(rows, extra...)
+		if err != nil {
+			return nil, err
+		}
+		if search != "" {
+			highlight := highlightHTML(headline)
+			transaction.Rank = &rank
+			transaction.Highlight = &highlight
+		}
+		transactions = append(transactions, *transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	more := len(transactions) > filters.Limit
+	if more {
+		transactions = transactions[:filters.Limit]
+	}
+	if backward {
+		for i, j := 0, len(transactions)-1; i < j; i, j = i+1, j-1 {
+			transactions[i], transactions[j] = transactions[j], transactions[i]
+		}
+	}
+
+	if len(transactions) > 0 {
+		first := transactionCursor(order, &transactions[0])
+		last := transactionCursor(order, &transactions[len(transactions)-1])
+		if backward {
+			page.NextCursor = last
+			if more {
+				page.PrevCursor = first
+			}
+		} else {
+			if more {
+				page.NextCursor = last
+			}
+			if cursor != nil || offset > 0 {
+				page.PrevCursor = first
+			}
+		}
+	}
+
+	if err := r.attachSplits(transactions); err != nil {
+		return nil, err
+	}
+	if err := r.attachTags(transactions); err != nil {
+		return nil, err
+	}
+
+	page.Transactions = transactions
+	return page, nil
+}
+
+// DateRange returns the dates of the oldest and newest transactions matching
+// the filters. Both are nil when nothing matches.
+func (r *TransactionRepository) DateRange(userID uuid.UUID, filters models.TransactionFilters) (*time.Time, *time.Time, error) {
+	whereClause, args := transactionFilterClause(userID, filters)
+
+	var first, last sql.NullTime
+	query := fmt.Sprintf("SELECT MIN(t.date), MAX(t.date) FROM transactions t WHERE %s", whereClause)
+	if err := r.db.QueryRow(query, args...).Scan(&first, &last); err != nil {
+		return nil, nil, err
+	}
+
+	if !first.Valid {
+		return nil, nil, nil
+	}
+	return &first.Time, &last.Time, nil
+}
+
+// Currencies returns the distinct currencies of the transactions matching the
+// filters.
+func (r *TransactionRepository) Currencies(userID uuid.UUID, filters models.TransactionFilters) ([]string, error) {
+	whereClause, args := transactionFilterClause(userID, filters)
+
+	query := fmt.Sprintf("SELECT DISTINCT t.currency FROM transactions t WHERE %s ORDER BY t.currency", whereClause)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var currencies []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, currency)
+	}
+
+	return currencies, rows.Err()
+}
+
+// exportBatchSize is the number of transactions Export loads per query.
+const exportBatchSize = 500
+
+// Export calls fn for every transaction matching the filters, in the order of
+// filters.Sort (oldest first by default), without pagination. Transactions
+// are loaded in batches (with their splits and tags) so memory use does not
+// grow with the result set; no query is left open while fn runs.
+func (r *TransactionRepository) Export(userID uuid.UUID, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	whereClause, args := transactionFilterClause(userID, filters)
+	argPos := len(args) + 1
+	order := transactionOrder(filters.Sort, transactionExportOrder)
+
+	var last *models.Transaction
+	for {
+		query := transactionSelect + "WHERE " + whereClause
+		batchArgs := args
+		if last != nil {
+			after, afterArgs := afterClause(order, sortValues(order, last), argPos)
+			query += " AND " + after
+			batchArgs = append(batchArgs[:len(batchArgs):len(batchArgs)], afterArgs...)
+		}
+		query += fmt.Sprintf(" ORDER BY %s LIMIT %d", orderByClause(order), exportBatchSize)
+
+		rows, err := r.db.Query(query, batchArgs...)
+		if err != nil {
+			return err
+		}
+
+		var transactions []models.Transaction
+		for rows.Next() {
+			transaction, err := scanTransaction(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			transactions = append(transactions, *transaction)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(transactions) == 0 {
+			return nil
+		}
+
+		if err := r.attachSplits(transactions); err != nil {
+			return err
+		}
+		if err := r.attachTags(transactions); err != nil {
+			return err
+		}
+
+		for i := range transactions {
+			if err := fn(&transactions[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(transactions) < exportBatchSize {
+			return nil
+		}
+		last = &transactions[len(transactions)-1]
+	}
+}
+
+// Update applies the column updates and replaces the split lines and tags of
+// the transaction when splits or tagIDs are not nil, in a single DB transaction.
+func (r *TransactionRepository) Update(id, userID uuid.UUID, updates map[string]interface{}, splits []models.TransactionSplit, tagIDs []uuid.UUID) error {
+	if len(updates) == 0 && splits == nil && tagIDs == nil {
+		return fmt.Errorf("no fields to update")
+	}
+
+	updates["updated_at"] = time.Now()
+
+	var setClauses []string
+	var args []interface{}
+	argPos := 1
+
+	for field, value := range updates {
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", field, argPos))
+		args = append(args, value)
+		argPos++
+	}
+
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf(
+		"UPDATE transactions SET %s WHERE id = $%d AND user_id = $%d AND deleted_at IS NULL",
+		strings.Join(setClauses, ", "),
+		argPos,
+		argPos+1,
+	)
+
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("transaction not found")
+	}
+
+	if splits != nil {
+		if _, err := tx.Exec("DELETE FROM transaction_splits WHERE transaction_id = $1", id); err != nil {
+			return err
+		}
+		if err := insertSplits(tx, id, splits); err != nil {
+			return err
+		}
+	}
+
+	if tagIDs != nil {
+		if err := setTags(tx, id, userID, tagIDs); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete moves a transaction to the trash. When the transaction is a transfer
+// leg the counterpart leg is moved as well so transfers never end up
+// half-deleted.
+func (r *TransactionRepository) Delete(id, userID uuid.UUID) error {
+	query := `
+		UPDATE transactions SET deleted_at = $3
+		WHERE user_id = $2 AND deleted_at IS NULL AND (
+			id = $1 OR transfer_id = (
+				SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+			)
+		)
+	`
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("transaction not found")
+	}
+
+	return nil
+}
+
+// GetDeleted lists the transactions in the trash, most recently deleted
+// first.
+func (r *TransactionRepository) GetDeleted(userID uuid.UUID) ([]models.Transaction, error) {
+	query := `
+		SELECT ` + transactionColumns + `, t.deleted_at` + transactionFrom + `
+		WHERE t.user_id = $1 AND t.deleted_at IS NOT NULL
+		ORDER BY t.deleted_at DESC, t.date DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		var deletedAt *time.Time
+		transaction, err := scanTransaction(rows, &deletedAt)
+		if err != nil {
+			return nil, err
+		}
+		transaction.DeletedAt = deletedAt
+		transactions = append(transactions, *transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachSplits(transactions); err != nil {
+		return nil, err
+	}
+	if err := r.attachTags(transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// Restore takes a transaction out of the trash, together with the other leg
+// when it is a transfer.
+func (r *TransactionRepository) Restore(id, userID uuid.UUID) error {
+	query := `
+		UPDATE transactions SET deleted_at = NULL
+		WHERE user_id = $2 AND deleted_at IS NOT NULL AND (
+			id = $1 OR transfer_id = (
+				SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+			)
+		)
+	`
+
+	result, err := auditedExec(r.db, r.audit, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotInTrash
+	}
+
+	return nil
+}
+
+func (r *TransactionRepository) GetRecentTransactions(userID uuid.UUID, limit int) ([]models.Transaction, error) {
+	query := transactionSelect + `
+		WHERE t.user_id = $1 AND t.deleted_at IS NULL
+		ORDER BY t.date DESC, t.created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, *transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachSplits(transactions); err != nil {
+		return nil, err
+	}
+	if err := r.attachTags(transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// CreateTransfer writes both legs of a transfer atomically and links them
+// through a shared transfer_id.
+func (r *TransactionRepository) CreateTransfer(userID uuid.UUID, req models.CreateTransferRequest) (*models.Transfer, error) {
+	tx, err := auditedBegin(r.db, r.audit)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	transferID := uuid.New()
+	fromAccountID := req.FromAccountID
+	toAccountID := req.ToAccountID
+
+	outgoing := &models.Transaction{
+		UserID:      userID,
+		AccountID:   &fromAccountID,
+		TransferID:  &transferID,
+		Type:        "expense",
+		Amount:      req.Amount,
+		Description: req.Description,
+		Date:        req.Date,
+	}
+	if err := insertTransaction(tx, outgoing); err != nil {
+		return nil, err
+	}
+
+	toAmount := req.Amount
+	if req.ToAmount != nil {
+		toAmount = *req.ToAmount
+	}
+
+	incoming := &models.Transaction{
+		UserID:      userID,
+		AccountID:   &toAccountID,
+		TransferID:  &transferID,
+		Type:        "income",
+		Amount:      toAmount,
+		Description: req.Description,
+		Date:        req.Date,
+	}
+	if err := insertTransaction(tx, incoming); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.Transfer{
+		ID:            transferID,
+		FromAccountID: fromAccountID,
+		ToAccountID:   toAccountID,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		Date:          req.Date,
+		Outgoing:      outgoing,
+		Incoming:      incoming,
+	}, nil
+}
+
+func (r *TransactionRepository) GetTransfer(transferID, userID uuid.UUID) (*models.Transfer, error) {
+	query := transactionSelect + `
+		WHERE t.transfer_id = $1 AND t.user_id = $2 AND t.deleted_at IS NULL
+	`
+
+	rows, err := r.db.Query(query, transferID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfer := &models.Transfer{ID: transferID}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		if transaction.Type == "expense" {
+			transfer.Outgoing = transaction
+		} else {
+			transfer.Incoming = transaction
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if transfer.Outgoing == nil || transfer.Incoming == nil {
+		return nil, fmt.Errorf("transfer not found")
+	}
+
+	if transfer.Outgoing.AccountID != nil {
+		transfer.FromAccountID = *transfer.Outgoing.AccountID
+	}
+	if transfer.Incoming.AccountID != nil {
+		transfer.ToAccountID = *transfer.Incoming.AccountID
+	}
+	transfer.Amount = transfer.Outgoing.Amount
+	transfer.Description = transfer.Outgoing.Description
+	transfer.Date = transfer.Outgoing.Date
+
+	return transfer, nil
+}
+
+// DeleteTransfer moves both legs of a transfer to the trash.
+func (r *TransactionRepository) DeleteTransfer(transferID, userID uuid.UUID) error {
+	query := "UPDATE transactions SET deleted_at = $3 WHERE transfer_id = $1 AND user_id = $2 AND deleted_at IS NULL"
+
+	result, err := auditedExec(r.db, r.audit, query, transferID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("transfer not found")
+	}
+
+	return nil
+}
+
+func insertSplits(q queryer, transactionID uuid.UUID, splits []models.TransactionSplit) error {
+	query := `
+		INSERT INTO transaction_splits (id, transaction_id, category_id, amount, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for i := range splits {
+		splits[i].ID = uuid.New()
+		splits[i].TransactionID = transactionID
+		splits[i].CreatedAt = time.Now()
+
+		if _, err := q.Exec(
+			query,
+			splits[i].ID,
+			splits[i].TransactionID,
+			splits[i].CategoryID,
+			splits[i].Amount,
+			splits[i].Note,
+			splits[i].CreatedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachSplits loads the split lines of the given transactions with a single
+// query and assigns them in place.
+func (r *TransactionRepository) attachSplits(transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(transactions))
+	index := make(map[uuid.UUID]int, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID.String()
+		index[transaction.ID] = i
+	}
+
+	query := `
+		SELECT
+			s.id, s.transaction_id, s.category_id, s.amount, s.note, s.created_at,
+			c.id, c.user_id, c.name, c.type, c.color, c.icon, c.created_at
+		FROM transaction_splits s
+		LEFT JOIN categories c ON s.category_id = c.id AND c.deleted_at IS NULL
+		WHERE s.transaction_id = ANY($1::uuid[])
+		ORDER BY s.amount DESC, s.created_at ASC
+	`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var split models.TransactionSplit
+		var category nullableCategory
+
+		dest := []interface{}{
+			&split.ID,
+			&split.TransactionID,
+			&split.CategoryID,
+			&split.Amount,
+			&split.Note,
+			&split.CreatedAt,
+		}
+		if err := rows.Scan(append(dest, category.dest()...)...); err != nil {
+			return err
+		}
+
+		split.Category = category.category()
+		i := index[split.TransactionID]
+		transactions[i].Splits = append(transactions[i].Splits, split)
+	}
+
+	return rows.Err()
+}
+
+// setTags replaces the tags of a transaction. Tag IDs that don't belong to
+// the user are ignored.
+func setTags(q queryer, transactionID, userID uuid.UUID, tagIDs []uuid.UUID) error {
+	if _, err := q.Exec("DELETE FROM transaction_tags WHERE transaction_id = $1", transactionID); err != nil {
+		return err
+	}
+
+	return addTags(q, transactionID, userID, tagIDs)
+}
+
+// addTags links the transaction to the given tags, ignoring tags that do not
+// belong to the user or are already linked.
+func addTags(q queryer, transactionID, userID uuid.UUID, tagIDs []uuid.UUID) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(tagIDs))
+	for i, id := range tagIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		INSERT INTO transaction_tags (transaction_id, tag_id)
+		SELECT $1, id FROM tags WHERE user_id = $2 AND id = ANY($3::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := q.Exec(query, transactionID, userID, pq.Array(ids))
+	return err
+}
+
+// attachTags loads the tags of the given transactions with a single query and
+// assigns them in place.
+func (r *TransactionRepository) attachTags(transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(transactions))
+	index := make(map[uuid.UUID]int, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID.String()
+		index[transaction.ID] = i
+	}
+
+	query := `
+		SELECT tt.transaction_id, tg.id, tg.user_id, tg.name, tg.color, tg.created_at
+		FROM transaction_tags tt
+		JOIN tags tg ON tg.id = tt.tag_id
+		WHERE tt.transaction_id = ANY($1::uuid[])
+		ORDER BY tg.name ASC
+	`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transactionID uuid.UUID
+		var tag models.Tag
+		if err := rows.Scan(
+			&transactionID,
+			&tag.ID,
+			&tag.UserID,
+			&tag.Name,
+			&tag.Color,
+			&tag.CreatedAt,
+		); err != nil {
+			return err
+		}
+
+		i := index[transactionID]
+		transactions[i].Tags = append(transactions[i].Tags, tag)
+	}
+
+	return rows.Err()
+}
+
+// highlightHTML escapes a ts_headline snippet and marks the matched terms
+// with <mark> tags.
+func highlightHTML(headline string) string {
+	return strings.NewReplacer(
+		highlightStart, "<mark>",
+		highlightStop, "</mark>",
+	).Replace(html.EscapeString(headline))
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
 }
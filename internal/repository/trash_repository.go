@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNotInTrash is returned when restoring a record that is not in the trash.
+var ErrNotInTrash = errors.New("not found in trash")
+
+// TrashRepository permanently removes the records left in the trash.
+type TrashRepository struct {
+	db *sql.DB
+}
+
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// trashTables are purged in this order, records before the categories they
+// may still reference.
+var trashTables = []string{"transactions", "budgets", "financial_goals", "categories"}
+
+// Purge deletes, across all users and in a single DB transaction, the records
+// moved to the trash before the given time. It returns how many were deleted
+// per table and the storage keys of the attachments that went with the
+// transactions, whose files the caller must remove.
+func (r *TrashRepository) Purge(before time.Time) (map[string]int, []string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT a.storage_key
+		FROM attachments a
+		JOIN transactions t ON t.id = a.transaction_id
+		WHERE t.deleted_at < $1
+	`, before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var storageKeys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		storageKeys = append(storageKeys, key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	counts := make(map[string]int, len(trashTables))
+	for _, table := range trashTables {
+		result, err := tx.Exec("DELETE FROM "+table+" WHERE deleted_at < $1", before)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, nil, err
+		}
+		counts[table] = int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return counts, storageKeys, nil
+}
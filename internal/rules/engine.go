@@ -0,0 +1,162 @@
+// Package rules applies the user's categorization rules to transactions.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Match types.
+const (
+	MatchContains = "contains"
+	MatchRegex    = "regex"
+)
+
+// ValidatePattern checks that a rule pattern can be compiled.
+func ValidatePattern(matchType, pattern string) error {
+	if matchType != MatchRegex {
+		return nil
+	}
+	if _, err := compile(pattern); err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return nil
+}
+
+// compile builds a case-insensitive regular expression.
+func compile(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+type compiledRule struct {
+	rule     models.CategorizationRule
+	contains string
+	regex    *regexp.Regexp
+}
+
+// Engine evaluates a user's active rules, highest priority first.
+type Engine struct {
+	rules []compiledRule
+}
+
+// NewEngine prepares the active rules. Rules whose pattern no longer
+// compiles are skipped.
+func NewEngine(rules []models.CategorizationRule) *Engine {
+	engine := &Engine{}
+	for _, rule := range rules {
+		if !rule.Active {
+			continue
+		}
+
+		compiled := compiledRule{rule: rule}
+		if rule.Pattern != nil && *rule.Pattern != "" {
+			if rule.MatchType == MatchRegex {
+				regex, err := compile(*rule.Pattern)
+				if err != nil {
+					continue
+				}
+				compiled.regex = regex
+			} else {
+				compiled.contains = strings.ToLower(*rule.Pattern)
+			}
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+
+	sort.SliceStable(engine.rules, func(i, j int) bool {
+		return engine.rules[i].rule.Priority > engine.rules[j].rule.Priority
+	})
+
+	return engine
+}
+
+func (r *compiledRule) matches(transaction *models.Transaction) bool {
+	description := ""
+	if transaction.Description != nil {
+		description = *transaction.Description
+	}
+
+	switch {
+	case r.regex != nil && !r.regex.MatchString(description):
+		return false
+	case r.contains != "" && !strings.Contains(strings.ToLower(description), r.contains):
+		return false
+	case r.rule.TransactionType != nil && *r.rule.TransactionType != transaction.Type:
+		return false
+	case r.rule.MinAmount != nil && transaction.Amount < *r.rule.MinAmount:
+		return false
+	case r.rule.MaxAmount != nil && transaction.Amount > *r.rule.MaxAmount:
+		return false
+	}
+	return true
+}
+
+// Match returns the first rule matching the transaction, or nil. Transfers
+// never match.
+func (e *Engine) Match(transaction *models.Transaction) *models.CategorizationRule {
+	if transaction.TransferID != nil {
+		return nil
+	}
+
+	for i := range e.rules {
+		if e.rules[i].matches(transaction) {
+			return &e.rules[i].rule
+		}
+	}
+	return nil
+}
+
+// Apply sets the category, tags and description of the first matching rule
+// on the transaction and returns what changed, or nil when nothing did. The
+// category is only set on uncategorized transactions unless overwrite is true,
+// and never on split transactions, whose lines carry the categories. Tags are
+// added to the existing ones.
+func (e *Engine) Apply(transaction *models.Transaction, overwrite bool) *models.RuleChange {
+	rule := e.Match(transaction)
+	if rule == nil {
+		return nil
+	}
+
+	change := &models.RuleChange{TransactionID: transaction.ID, RuleID: rule.ID}
+	changed := false
+
+	if rule.SetCategoryID != nil && len(transaction.Splits) == 0 &&
+		(transaction.CategoryID == nil || (overwrite && *transaction.CategoryID != *rule.SetCategoryID)) {
+		categoryID := *rule.SetCategoryID
+		transaction.CategoryID = &categoryID
+		transaction.Category = nil
+		change.CategoryID = &categoryID
+		changed = true
+	}
+
+	if rule.SetDescription != nil && (transaction.Description == nil || *transaction.Description != *rule.SetDescription) {
+		description := *rule.SetDescription
+		transaction.Description = &description
+		change.Description = &description
+		changed = true
+	}
+
+	existing := make(map[uuid.UUID]bool, len(transaction.Tags))
+	for _, tag := range transaction.Tags {
+		existing[tag.ID] = true
+	}
+	for _, tagID := range rule.SetTagIDs {
+		if existing[tagID] {
+			continue
+		}
+		existing[tagID] = true
+		transaction.Tags = append(transaction.Tags, models.Tag{ID: tagID})
+		change.AddTagIDs = append(change.AddTagIDs, tagID)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return change
+}
@@ -0,0 +1,76 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Gildaciolopes/fintrack-api/internal/repository"
+	"github.com/Gildaciolopes/fintrack-api/internal/storage"
+)
+
+// Purger periodically removes for good the records that have been in the
+// trash for longer than the retention period, along with the files of the
+// attachments of purged transactions.
+type Purger struct {
+	repo      *repository.TrashRepository
+	store     storage.Storage
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewPurger(repo *repository.TrashRepository, store storage.Storage, retention, interval time.Duration) *Purger {
+	return &Purger{
+		repo:      repo,
+		store:     store,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Start runs the purger immediately and then on every tick until ctx is
+// cancelled. It is meant to be launched in its own goroutine.
+func (p *Purger) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.RunOnce(ctx, time.Now())
+		if err != nil {
+			log.Printf("Trash purger failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("Trash purger removed %d records", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce purges everything moved to the trash more than the retention period
+// before now and returns how many records were removed. Files that cannot be
+// removed are only logged: their rows are already gone.
+func (p *Purger) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	counts, storageKeys, err := p.repo.Purge(now.Add(-p.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range storageKeys {
+		err := p.store.Delete(ctx, key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Trash purger failed to remove %s: %v", key, err)
+		}
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	return total, nil
+}
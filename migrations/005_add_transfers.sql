@@ -0,0 +1,7 @@
+-- Transfers between two of the user's accounts are stored as a pair of
+-- transactions (an expense on the source account and an income on the
+-- destination account) sharing the same transfer_id.
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS transfer_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_transfer_id ON transactions(transfer_id) WHERE transfer_id IS NOT NULL;
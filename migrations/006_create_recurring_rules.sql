@@ -0,0 +1,29 @@
+-- Templates for transactions that repeat (rent, salary, subscriptions).
+CREATE TABLE IF NOT EXISTS recurring_rules (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+  interval INTEGER NOT NULL DEFAULT 1 CHECK (interval > 0),
+  start_date DATE NOT NULL,
+  end_date DATE,
+  day_of_month INTEGER CHECK (day_of_month BETWEEN 1 AND 31),
+  category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+  account_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+  type VARCHAR(10) NOT NULL CHECK (type IN ('income', 'expense')),
+  amount NUMERIC(14, 2) NOT NULL CHECK (amount > 0),
+  description TEXT,
+  active BOOLEAN NOT NULL DEFAULT true,
+  last_run_date DATE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_user_id ON recurring_rules(user_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_active ON recurring_rules(active, start_date);
+
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS recurring_rule_id UUID REFERENCES recurring_rules(id) ON DELETE SET NULL;
+
+-- A rule materializes at most one transaction per occurrence date.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_recurring_occurrence
+  ON transactions(recurring_rule_id, date) WHERE recurring_rule_id IS NOT NULL;
@@ -0,0 +1,14 @@
+-- Split lines break one transaction down into several categories. When a
+-- transaction has splits, the lines must add up to the transaction amount and
+-- category reports use the lines instead of the transaction category.
+CREATE TABLE IF NOT EXISTS transaction_splits (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+  amount NUMERIC(14, 2) NOT NULL CHECK (amount > 0),
+  note TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_transaction_id ON transaction_splits(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_category_id ON transaction_splits(category_id);
@@ -0,0 +1,42 @@
+-- Per-user preferences, starting with the currency reports are converted to.
+CREATE TABLE IF NOT EXISTS user_settings (
+  user_id UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+  base_currency CHAR(3) NOT NULL DEFAULT 'BRL',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+
+-- Reference rates expressed as units of currency per 1 EUR (the ECB
+-- convention). EUR itself is implicitly 1.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+  currency CHAR(3) NOT NULL,
+  rate_date DATE NOT NULL,
+  rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+  source VARCHAR(20) NOT NULL DEFAULT 'import',
+  PRIMARY KEY (currency, rate_date)
+);
+
+-- fx_rate returns the EUR-based rate of a currency on a date: the latest rate
+-- published on or before the date, or the earliest one after it when the
+-- date predates the imported history. NULL when the currency is unknown.
+CREATE OR REPLACE FUNCTION fx_rate(p_currency CHAR(3), p_date DATE)
+RETURNS NUMERIC AS $$
+  SELECT CASE WHEN p_currency = 'EUR' THEN 1::NUMERIC ELSE COALESCE(
+    (SELECT rate FROM exchange_rates WHERE currency = p_currency AND rate_date <= p_date ORDER BY rate_date DESC LIMIT 1),
+    (SELECT rate FROM exchange_rates WHERE currency = p_currency AND rate_date > p_date ORDER BY rate_date ASC LIMIT 1)
+  ) END
+$$ LANGUAGE SQL STABLE;
+
+-- fx_convert converts an amount between currencies using the rates of the
+-- given date, rounded to cents. Amounts in a currency without any rate are
+-- returned unconverted.
+CREATE OR REPLACE FUNCTION fx_convert(p_amount NUMERIC, p_from CHAR(3), p_to CHAR(3), p_date DATE)
+RETURNS NUMERIC AS $$
+  SELECT CASE WHEN p_from = p_to THEN p_amount ELSE COALESCE(
+    ROUND(p_amount / fx_rate(p_from, p_date) * fx_rate(p_to, p_date), 2),
+    p_amount
+  ) END
+$$ LANGUAGE SQL STABLE;
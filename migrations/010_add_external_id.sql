@@ -0,0 +1,8 @@
+-- Identifier of a transaction in the bank statement it was imported from
+-- (the OFX FITID, scoped by account), used to skip rows already imported.
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_user_external_id
+  ON transactions(user_id, external_id)
+  WHERE external_id IS NOT NULL;
@@ -0,0 +1,23 @@
+-- User-defined rules that fill in the category, tags or description of new
+-- and imported transactions. Active rules are evaluated by descending
+-- priority and the first match wins.
+CREATE TABLE IF NOT EXISTS categorization_rules (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  priority INTEGER NOT NULL DEFAULT 0,
+  active BOOLEAN NOT NULL DEFAULT true,
+  match_type VARCHAR(10) NOT NULL DEFAULT 'contains' CHECK (match_type IN ('contains', 'regex')),
+  pattern TEXT,
+  transaction_type VARCHAR(10) CHECK (transaction_type IN ('income', 'expense')),
+  min_amount NUMERIC(15, 2),
+  max_amount NUMERIC(15, 2),
+  set_category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+  set_tag_ids UUID[] NOT NULL DEFAULT '{}',
+  set_description TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_categorization_rules_user_priority
+  ON categorization_rules(user_id, priority DESC);
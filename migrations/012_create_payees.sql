@@ -0,0 +1,17 @@
+-- Merchants and other counterparties. Aliases are the ways a payee shows up
+-- in transaction descriptions ("uber trip", "uber bv"), matched as whole
+-- words after normalization.
+CREATE TABLE IF NOT EXISTS payees (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  aliases TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, name)
+);
+
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS payee_id UUID REFERENCES payees(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_payee_id ON transactions(payee_id);
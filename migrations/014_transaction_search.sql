@@ -0,0 +1,113 @@
+-- Full-text search over transactions. fintrack_pt is the Portuguese
+-- configuration with accents folded, so "farmacia" finds "Farmácia".
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'fintrack_pt') THEN
+    CREATE TEXT SEARCH CONFIGURATION fintrack_pt (COPY = portuguese);
+    ALTER TEXT SEARCH CONFIGURATION fintrack_pt
+      ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+  END IF;
+END
+$$;
+
+-- transaction_search_text is the searchable text of a transaction: its
+-- description, payee, categories (including the ones of split lines) and
+-- split notes, in decreasing order of relevance.
+CREATE OR REPLACE FUNCTION transaction_search_text(p_id UUID, p_description TEXT, p_category_id UUID, p_payee_id UUID)
+RETURNS TEXT[] AS $$
+  SELECT ARRAY[
+    COALESCE(p_description, ''),
+    COALESCE((SELECT name FROM payees WHERE id = p_payee_id), ''),
+    concat_ws(' ',
+      (SELECT name FROM categories WHERE id = p_category_id),
+      (SELECT string_agg(c.name, ' ') FROM transaction_splits s JOIN categories c ON c.id = s.category_id WHERE s.transaction_id = p_id)
+    ),
+    COALESCE((SELECT string_agg(note, ' ') FROM transaction_splits WHERE transaction_id = p_id), '')
+  ]
+$$ LANGUAGE SQL STABLE;
+
+CREATE OR REPLACE FUNCTION transaction_search_vector(p_id UUID, p_description TEXT, p_category_id UUID, p_payee_id UUID)
+RETURNS tsvector AS $$
+  SELECT setweight(to_tsvector('fintrack_pt', doc[1]), 'A')
+    || setweight(to_tsvector('fintrack_pt', doc[2]), 'B')
+    || setweight(to_tsvector('fintrack_pt', doc[3]), 'C')
+    || setweight(to_tsvector('fintrack_pt', doc[4]), 'D')
+  FROM (SELECT transaction_search_text(p_id, p_description, p_category_id, p_payee_id) AS doc) d
+$$ LANGUAGE SQL STABLE;
+
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION transactions_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.search_vector := transaction_search_vector(NEW.id, NEW.description, NEW.category_id, NEW.payee_id);
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS transactions_search_vector ON transactions;
+CREATE TRIGGER transactions_search_vector
+  BEFORE INSERT OR UPDATE OF description, category_id, payee_id ON transactions
+  FOR EACH ROW EXECUTE FUNCTION transactions_search_vector_update();
+
+-- Changes to split lines and renamed categories or payees refresh the
+-- vectors of the transactions using them.
+CREATE OR REPLACE FUNCTION transaction_splits_search_vector_update()
+RETURNS TRIGGER AS $$
+DECLARE
+  v_transaction_id UUID;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    v_transaction_id := OLD.transaction_id;
+  ELSE
+    v_transaction_id := NEW.transaction_id;
+  END IF;
+
+  UPDATE transactions SET search_vector = transaction_search_vector(id, description, category_id, payee_id)
+  WHERE id = v_transaction_id;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS transaction_splits_search_vector ON transaction_splits;
+CREATE TRIGGER transaction_splits_search_vector
+  AFTER INSERT OR UPDATE OR DELETE ON transaction_splits
+  FOR EACH ROW EXECUTE FUNCTION transaction_splits_search_vector_update();
+
+CREATE OR REPLACE FUNCTION categories_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE transactions SET search_vector = transaction_search_vector(id, description, category_id, payee_id)
+  WHERE category_id = NEW.id
+    OR id IN (SELECT transaction_id FROM transaction_splits WHERE category_id = NEW.id);
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS categories_search_vector ON categories;
+CREATE TRIGGER categories_search_vector
+  AFTER UPDATE OF name ON categories
+  FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+  EXECUTE FUNCTION categories_search_vector_update();
+
+CREATE OR REPLACE FUNCTION payees_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE transactions SET search_vector = transaction_search_vector(id, description, category_id, payee_id)
+  WHERE payee_id = NEW.id;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS payees_search_vector ON payees;
+CREATE TRIGGER payees_search_vector
+  AFTER UPDATE OF name ON payees
+  FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+  EXECUTE FUNCTION payees_search_vector_update();
+
+UPDATE transactions SET search_vector = transaction_search_vector(id, description, category_id, payee_id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_search_vector ON transactions USING GIN (search_vector);
@@ -0,0 +1,13 @@
+-- Deleted transactions, categories, budgets and goals go to the trash first:
+-- deleted_at is set and reads leave them out. They can be restored until the
+-- purge job removes them for good once the retention period has passed.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE financial_goals ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Trash listings and the purge job only look at deleted rows.
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_budgets_deleted_at ON budgets(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_financial_goals_deleted_at ON financial_goals(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,130 @@
+-- Append-only history of the changes made to the user's records. Rows are
+-- written by triggers, so every insert, update and delete is recorded however
+-- it was made. The API tells the triggers who made the change, and from which
+-- request, through transaction-local settings (fintrack.actor_id,
+-- fintrack.request_id and fintrack.ip); changes made by background jobs have
+-- no actor.
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  -- Not a foreign key: the history must outlive the records and cascading
+  -- deletes of a user would otherwise fail on the rows they write.
+  user_id UUID NOT NULL,
+  actor_id UUID,
+  entity_type VARCHAR(30) NOT NULL,
+  entity_id UUID NOT NULL,
+  action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge')),
+  before JSONB,
+  after JSONB,
+  request_id VARCHAR(100),
+  ip INET,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_user_id ON audit_log(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id, id DESC);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+-- audit_changes records one change of the row, the entity type being the
+-- trigger argument. Updates only keep the columns that changed and are
+-- skipped when nothing but bookkeeping columns did. Setting or clearing
+-- deleted_at is a delete or a restore (the trash); deleting a row that was in
+-- the trash is a purge.
+CREATE OR REPLACE FUNCTION audit_changes()
+RETURNS TRIGGER AS $$
+DECLARE
+  v_ignored CONSTANT TEXT[] := ARRAY['id', 'user_id', 'created_at', 'updated_at', 'search_vector', 'storage_key'];
+  v_old JSONB;
+  v_new JSONB;
+  v_row JSONB;
+  v_before JSONB := '{}';
+  v_after JSONB := '{}';
+  v_action TEXT;
+  v_key TEXT;
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    v_old := to_jsonb(OLD);
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    v_new := to_jsonb(NEW);
+  END IF;
+  v_row := COALESCE(v_new, v_old);
+
+  IF TG_OP = 'INSERT' THEN
+    v_action := 'create';
+    v_after := v_new - v_ignored;
+  ELSIF TG_OP = 'DELETE' THEN
+    v_action := CASE WHEN v_old->>'deleted_at' IS NOT NULL THEN 'purge' ELSE 'delete' END;
+    v_before := v_old - v_ignored;
+  ELSE
+    FOR v_key IN SELECT jsonb_object_keys(v_new) LOOP
+      IF NOT v_key = ANY(v_ignored) AND v_new->v_key IS DISTINCT FROM v_old->v_key THEN
+        v_before := v_before || jsonb_build_object(v_key, v_old->v_key);
+        v_after := v_after || jsonb_build_object(v_key, v_new->v_key);
+      END IF;
+    END LOOP;
+
+    IF v_after = '{}' THEN
+      RETURN NULL;
+    END IF;
+
+    v_action := CASE
+      WHEN v_old->>'deleted_at' IS NULL AND v_new->>'deleted_at' IS NOT NULL THEN 'delete'
+      WHEN v_old->>'deleted_at' IS NOT NULL AND v_new->>'deleted_at' IS NULL THEN 'restore'
+      ELSE 'update'
+    END;
+  END IF;
+
+  INSERT INTO audit_log (user_id, actor_id, entity_type, entity_id, action, before, after, request_id, ip)
+  VALUES (
+    (v_row->>'user_id')::uuid,
+    NULLIF(current_setting('fintrack.actor_id', true), '')::uuid,
+    TG_ARGV[0],
+    COALESCE(v_row->>'id', v_row->>'user_id')::uuid,
+    v_action,
+    NULLIF(v_before, '{}'),
+    NULLIF(v_after, '{}'),
+    NULLIF(current_setting('fintrack.request_id', true), ''),
+    NULLIF(current_setting('fintrack.ip', true), '')::inet
+  );
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+  v_table TEXT;
+  v_entity TEXT;
+BEGIN
+  FOR v_table, v_entity IN SELECT * FROM (VALUES
+    ('transactions', 'transaction'),
+    ('categories', 'category'),
+    ('budgets', 'budget'),
+    ('financial_goals', 'goal'),
+    ('accounts', 'account'),
+    ('tags', 'tag'),
+    ('payees', 'payee'),
+    ('recurring_rules', 'recurring_rule'),
+    ('categorization_rules', 'categorization_rule'),
+    ('attachments', 'attachment'),
+    ('user_settings', 'settings')
+  ) AS audited(table_name, entity_type)
+  LOOP
+    EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', v_table || '_audit', v_table);
+    EXECUTE format(
+      'CREATE TRIGGER %I AFTER INSERT OR UPDATE OR DELETE ON %I FOR EACH ROW EXECUTE FUNCTION audit_changes(%L)',
+      v_table || '_audit', v_table, v_entity
+    );
+  END LOOP;
+END
+$$;
@@ -0,0 +1,4 @@
+-- With rollover, what is left of a budget at the end of its month, or the
+-- overspend as a negative amount, carries into next month's budget of the
+-- same category.
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS rollover BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,18 @@
+-- Named sets of budgets that can be applied to any month. Items hold the
+-- category_id, amount and rollover of each budget; categories that were
+-- deleted since are skipped when the template is applied.
+CREATE TABLE IF NOT EXISTS budget_templates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  items JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_templates_user_id ON budget_templates(user_id);
+
+DROP TRIGGER IF EXISTS budget_templates_audit ON budget_templates;
+CREATE TRIGGER budget_templates_audit
+  AFTER INSERT OR UPDATE OR DELETE ON budget_templates
+  FOR EACH ROW EXECUTE FUNCTION audit_changes('budget_template');
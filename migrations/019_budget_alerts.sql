@@ -0,0 +1,22 @@
+-- Percentages of a budget's available amount at which the user is alerted,
+-- e.g. {50,80,100}.
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS alert_thresholds INTEGER[] NOT NULL DEFAULT '{}';
+
+-- One alert per budget and threshold: a threshold crossed again after the
+-- spend went back under it is not alerted twice in the same month.
+CREATE TABLE IF NOT EXISTS budget_alerts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+  category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+  month DATE NOT NULL,
+  threshold INTEGER NOT NULL,
+  available NUMERIC(14, 2) NOT NULL,
+  spent NUMERIC(14, 2) NOT NULL,
+  percentage NUMERIC NOT NULL,
+  read_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (budget_id, month, threshold)
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_alerts_user_id ON budget_alerts(user_id, created_at DESC);
@@ -0,0 +1,20 @@
+-- fx_rate returns the EUR-based rate of a currency on a date: the latest rate
+-- published on or before the date. NULL when there is none, so amounts are
+-- never converted with a rate from after the transaction.
+CREATE OR REPLACE FUNCTION fx_rate(p_currency CHAR(3), p_date DATE)
+RETURNS NUMERIC AS $$
+  SELECT CASE WHEN p_currency = 'EUR' THEN 1::NUMERIC ELSE
+    (SELECT rate FROM exchange_rates WHERE currency = p_currency AND rate_date <= p_date ORDER BY rate_date DESC LIMIT 1)
+  END
+$$ LANGUAGE SQL STABLE;
+
+-- fx_convert converts an amount between currencies using the rates of the
+-- given date, rounded to cents. It returns NULL when either currency has no
+-- rate on or before the date: aggregates leave those amounts out and report
+-- the currency as missing instead of counting them unconverted.
+CREATE OR REPLACE FUNCTION fx_convert(p_amount NUMERIC, p_from CHAR(3), p_to CHAR(3), p_date DATE)
+RETURNS NUMERIC AS $$
+  SELECT CASE WHEN p_from = p_to THEN p_amount ELSE
+    ROUND(p_amount / fx_rate(p_from, p_date) * fx_rate(p_to, p_date), 2)
+  END
+$$ LANGUAGE SQL STABLE;
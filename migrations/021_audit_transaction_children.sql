@@ -0,0 +1,102 @@
+-- Tags and split lines have no history of their own: changing them is
+-- recorded as an update of their transaction, with the full list of tag IDs
+-- (or split lines) before and after each statement. The trigger argument is
+-- the key of the list in before/after and, optionally, the column a list
+-- entry is made of; otherwise entries are the rows without bookkeeping
+-- columns. Rows removed along with their transaction (a purge) are not
+-- recorded: the purge of the transaction is.
+CREATE OR REPLACE FUNCTION audit_transaction_children()
+RETURNS TRIGGER AS $$
+DECLARE
+  v_ignored CONSTANT TEXT[] := ARRAY['id', 'transaction_id', 'created_at'];
+  v_added JSONB := '[]';
+  v_removed JSONB := '[]';
+BEGIN
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    SELECT COALESCE(jsonb_agg(to_jsonb(n)), '[]') INTO v_added FROM new_rows n;
+  END IF;
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    SELECT COALESCE(jsonb_agg(to_jsonb(o)), '[]') INTO v_removed FROM old_rows o;
+  END IF;
+
+  EXECUTE format($query$
+    WITH changed AS (
+      SELECT DISTINCT (r->>'transaction_id')::uuid AS transaction_id
+      FROM jsonb_array_elements($1 || $2) r
+    ),
+    after_rows AS (
+      SELECT x.transaction_id, to_jsonb(x) AS r
+      FROM %I x
+      JOIN changed c ON c.transaction_id = x.transaction_id
+    ),
+    before_rows AS (
+      (
+        SELECT transaction_id, r FROM after_rows
+        EXCEPT ALL
+        SELECT (a->>'transaction_id')::uuid, a FROM jsonb_array_elements($1) a
+      )
+      UNION ALL
+      SELECT (d->>'transaction_id')::uuid, d FROM jsonb_array_elements($2) d
+    ),
+    lists AS (
+      SELECT
+        t.user_id,
+        t.id,
+        (SELECT COALESCE(jsonb_agg(CASE WHEN $4 IS NULL THEN b.r - $3 ELSE b.r->$4 END ORDER BY b.r), '[]')
+         FROM before_rows b WHERE b.transaction_id = t.id) AS before,
+        (SELECT COALESCE(jsonb_agg(CASE WHEN $4 IS NULL THEN a.r - $3 ELSE a.r->$4 END ORDER BY a.r), '[]')
+         FROM after_rows a WHERE a.transaction_id = t.id) AS after
+      FROM transactions t
+      JOIN changed c ON c.transaction_id = t.id
+    )
+    INSERT INTO audit_log (user_id, actor_id, entity_type, entity_id, action, before, after, request_id, ip)
+    SELECT
+      user_id,
+      NULLIF(current_setting('fintrack.actor_id', true), '')::uuid,
+      'transaction',
+      id,
+      'update',
+      jsonb_build_object(%L, before),
+      jsonb_build_object(%L, after),
+      NULLIF(current_setting('fintrack.request_id', true), ''),
+      NULLIF(current_setting('fintrack.ip', true), '')::inet
+    FROM lists
+    WHERE before <> after
+  $query$, TG_TABLE_NAME, TG_ARGV[0], TG_ARGV[0])
+  USING v_added, v_removed, v_ignored, TG_ARGV[1];
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+  v_table TEXT;
+  v_args TEXT;
+BEGIN
+  FOR v_table, v_args IN SELECT * FROM (VALUES
+    ('transaction_tags', '''tags'', ''tag_id'''),
+    ('transaction_splits', '''splits''')
+  ) AS audited(table_name, trigger_args)
+  LOOP
+    EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', v_table || '_audit_insert', v_table);
+    EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', v_table || '_audit_update', v_table);
+    EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', v_table || '_audit_delete', v_table);
+    EXECUTE format(
+      'CREATE TRIGGER %I AFTER INSERT ON %I REFERENCING NEW TABLE AS new_rows
+       FOR EACH STATEMENT EXECUTE FUNCTION audit_transaction_children(%s)',
+      v_table || '_audit_insert', v_table, v_args
+    );
+    EXECUTE format(
+      'CREATE TRIGGER %I AFTER UPDATE ON %I REFERENCING OLD TABLE AS old_rows NEW TABLE AS new_rows
+       FOR EACH STATEMENT EXECUTE FUNCTION audit_transaction_children(%s)',
+      v_table || '_audit_update', v_table, v_args
+    );
+    EXECUTE format(
+      'CREATE TRIGGER %I AFTER DELETE ON %I REFERENCING OLD TABLE AS old_rows
+       FOR EACH STATEMENT EXECUTE FUNCTION audit_transaction_children(%s)',
+      v_table || '_audit_delete', v_table, v_args
+    );
+  END LOOP;
+END
+$$;